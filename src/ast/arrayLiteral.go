@@ -11,8 +11,8 @@ import (
 type ArrayLiteral struct {
 	Token     token.Token
 	Elements  []Expression
-	Generator bool
-	// or something like x for x in 1...9 if expr else expr then expr
+	Generator bool // the single element is computed for every iteration of the clauses e.g. [x * x for x in xs if x > 0]
+	Clauses   []*ComprehensionClause
 }
 
 func (a *ArrayLiteral) expressionNode() {}
@@ -30,7 +30,44 @@ func (a *ArrayLiteral) String() string {
 
 	out.WriteString("[")
 	out.WriteString(strings.Join(elements, ", "))
+	for _, clause := range a.Clauses {
+		out.WriteString(" ")
+		out.WriteString(clause.String())
+	}
 	out.WriteString("]")
 
 	return out.String()
 }
+
+// ComprehensionClause is a clause of an array comprehension, a for clause iterates over the iterable and an if clause
+// skips the iterations its condition is false for. Each clause is nested in the ones before it.
+type ComprehensionClause struct {
+	Token     token.Token // for or if
+	Value     *IdentifierLiteral
+	Index     *IdentifierLiteral // nil when the index is not bound
+	Iterable  Expression
+	Condition Expression // nil for a for clause
+}
+
+func (c *ComprehensionClause) TokenLiteral() string { return c.Token.Literal }
+
+func (c *ComprehensionClause) String() string {
+	var out bytes.Buffer
+
+	if c.Condition != nil {
+		out.WriteString("if ")
+		out.WriteString(c.Condition.String())
+		return out.String()
+	}
+
+	out.WriteString("for ")
+	out.WriteString(c.Value.String())
+	if c.Index != nil {
+		out.WriteString(", ")
+		out.WriteString(c.Index.String())
+	}
+	out.WriteString(" in ")
+	out.WriteString(c.Iterable.String())
+
+	return out.String()
+}
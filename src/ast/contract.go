@@ -0,0 +1,33 @@
+package ast
+
+import (
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// Clause is a condition of a contract, e.g. require x > 0. A function checks its require clauses before and its ensure
+// clauses after running its body, a struct checks its invariant clauses after every change.
+type Clause struct {
+	Token     token.Token // the require, ensure or invariant keyword
+	Condition Expression
+	Source    string // the clause as written, e.g. require x > 0
+}
+
+func (c *Clause) String() string {
+	return c.Token.Literal + " " + c.Condition.String()
+}
+
+// clauseList formats the clauses preceded by a space, it is empty when there are none
+func clauseList(clauses ...[]*Clause) string {
+	var out strings.Builder
+
+	for _, list := range clauses {
+		out.WriteString(strings.Join(slice.Map(list, func(clause *Clause) string {
+			return " " + clause.String()
+		}), ""))
+	}
+
+	return out.String()
+}
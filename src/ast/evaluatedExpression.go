@@ -0,0 +1,16 @@
+package ast
+
+import (
+	"Flow/src/token"
+)
+
+// EvaluatedExpression holds an already evaluated value, the environment stores expressions so values computed by the
+// evaluator, like loop variables, are bound through it. It is never produced by the parser.
+type EvaluatedExpression struct {
+	Token token.Token
+	Value interface{ Inspect() string }
+}
+
+func (ee *EvaluatedExpression) expressionNode()      {}
+func (ee *EvaluatedExpression) TokenLiteral() string { return ee.Token.Literal }
+func (ee *EvaluatedExpression) String() string       { return ee.Value.Inspect() }
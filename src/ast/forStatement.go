@@ -0,0 +1,81 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"Flow/src/token"
+)
+
+// ForStatement loops while the condition holds, without condition it loops until broken out of. The C-style form runs
+// Init once before the loop and Post after every iteration.
+type ForStatement struct {
+	Token     token.Token
+	Init      Statement  // nil unless C-style
+	Condition Expression // nil for an infinite loop
+	Post      Statement  // nil unless C-style
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+
+	if fs.Init != nil || fs.Post != nil {
+		if fs.Init != nil {
+			out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+		}
+		out.WriteString("; ")
+		if fs.Condition != nil {
+			out.WriteString(fs.Condition.String())
+		}
+		out.WriteString("; ")
+		if fs.Post != nil {
+			out.WriteString(fs.Post.String())
+		}
+		out.WriteString(" ")
+	} else if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+		out.WriteString(" ")
+	}
+
+	out.WriteString("{")
+	out.WriteString(fs.Body.String())
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// ForInStatement loops over the elements of the iterable, binding the element and optionally its index
+type ForInStatement struct {
+	Token    token.Token
+	Value    *IdentifierLiteral
+	Index    *IdentifierLiteral // nil when the index is not bound
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Literal }
+
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	out.WriteString(fs.Value.String())
+	if fs.Index != nil {
+		out.WriteString(", ")
+		out.WriteString(fs.Index.String())
+	}
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(" {")
+	out.WriteString(fs.Body.String())
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -8,9 +8,93 @@ import (
 )
 
 type FunctionLiteralExpression struct {
-	Token      token.Token
-	Parameters []*IdentifierLiteral
-	Body       *BlockStatement
+	Token          token.Token
+	TypeParameters []*TypeParameter // empty unless the function is generic, e.g. <T>(value T) => value
+	Parameters     []*Parameter
+	ReturnType     *IdentifierLiteral // nil when the return type is omitted, e.g. (a string) string => a
+	ReturnNullable bool               // the return type is followed by ?
+	Requires       []*Clause          // checked before running the body
+	Ensures        []*Clause          // checked after running the body, result is the returned value and old the parameters
+	Body           *BlockStatement
+}
+
+// Parameter is a parameter of a function, e.g. s Shape, values passed to a parameter typed by an interface have to
+// implement it. Optional parameters and parameters with a default value follow the required ones, a variadic
+// parameter comes last.
+type Parameter struct {
+	Name     *IdentifierLiteral
+	Type     *IdentifierLiteral // nil when the type is omitted
+	Nullable bool               // the type is followed by ?, only a nullable type accepts null
+	Optional bool               // the name is followed by ?, the parameter is null when its argument is missing
+	Default  Expression         // evaluated when the argument is missing, nil when there is no default value
+	Variadic bool               // the name is preceded by ..., the parameter collects the remaining arguments in an array
+}
+
+func (p *Parameter) String() string {
+	var out bytes.Buffer
+
+	if p.Variadic {
+		out.WriteString("...")
+	}
+	out.WriteString(p.Name.String())
+	if p.Optional {
+		out.WriteString("?")
+	}
+	if p.Type != nil {
+		out.WriteString(" " + typeString(p.Type, p.Nullable))
+	}
+	if p.Default != nil {
+		out.WriteString(" = " + p.Default.String())
+	}
+
+	return out.String()
+}
+
+// AcceptsNull reports whether the parameter can be null, a missing optional argument is null so its type is nullable
+func (p *Parameter) AcceptsNull() bool {
+	return p.Nullable || p.Optional
+}
+
+// Arity returns the least and most number of arguments the parameters take, most is -1 when the last one is variadic
+func Arity(parameters []*Parameter) (least, most int) {
+	for _, p := range parameters {
+		switch {
+		case p.Variadic:
+			return least, -1
+		case !p.Optional && p.Default == nil:
+			least++
+		}
+	}
+
+	return least, len(parameters)
+}
+
+// TakesArguments reports whether the parameters take the number of arguments
+func TakesArguments(parameters []*Parameter, count int) bool {
+	least, most := Arity(parameters)
+	return count >= least && (most < 0 || count <= most)
+}
+
+// ParameterAt returns the parameter taking the argument at index i, the arguments following the last parameter are
+// taken by it when it is variadic
+func ParameterAt(parameters []*Parameter, i int) (*Parameter, bool) {
+	if i < len(parameters) {
+		return parameters[i], true
+	}
+
+	if len(parameters) > 0 && parameters[len(parameters)-1].Variadic {
+		return parameters[len(parameters)-1], true
+	}
+
+	return nil, false
+}
+
+func typeString(typeName *IdentifierLiteral, nullable bool) string {
+	if nullable {
+		return typeName.String() + "?"
+	}
+
+	return typeName.String()
 }
 
 func (fl *FunctionLiteralExpression) expressionNode()      {}
@@ -23,11 +107,48 @@ func (fl *FunctionLiteralExpression) String() string {
 		params = append(params, p.String())
 	}
 
+	out.WriteString(TypeParameterList(fl.TypeParameters))
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(")")
+	signature := clauseList(fl.Requires, fl.Ensures)
+	if fl.ReturnType != nil {
+		signature = " " + typeString(fl.ReturnType, fl.ReturnNullable) + signature
+	}
+	if signature != "" {
+		out.WriteString(signature + " ")
+	}
 	out.WriteString(fl.Body.String())
 
 	return out.String()
 }
+
+// TypeParameter is a type parameter of a generic function or struct, e.g. T Shape, the type arguments are inferred
+// from the values passed for it and have to implement the constraint
+type TypeParameter struct {
+	Name       *IdentifierLiteral
+	Constraint *IdentifierLiteral // nil when any type is allowed
+}
+
+func (tp *TypeParameter) String() string {
+	if tp.Constraint == nil {
+		return tp.Name.String()
+	}
+
+	return tp.Name.String() + " " + tp.Constraint.String()
+}
+
+// TypeParameterList formats type parameters as <T, U Shape>, it is empty when there are none
+func TypeParameterList(typeParameters []*TypeParameter) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+
+	var params []string
+	for _, tp := range typeParameters {
+		params = append(params, tp.String())
+	}
+
+	return "<" + strings.Join(params, ", ") + ">"
+}
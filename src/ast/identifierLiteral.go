@@ -10,3 +10,6 @@ type IdentifierLiteral struct {
 func (i *IdentifierLiteral) expressionNode()      {}
 func (i *IdentifierLiteral) TokenLiteral() string { return i.Token.Literal }
 func (i *IdentifierLiteral) String() string       { return i.Value }
+
+// IsBlank reports whether the identifier is the blank identifier _, values bound to it are discarded
+func (i *IdentifierLiteral) IsBlank() bool { return i.Token.Type == token.BLANK }
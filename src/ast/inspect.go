@@ -0,0 +1,168 @@
+package ast
+
+import "reflect"
+
+// Inspect traverses the ast depth first calling fn for every node, the children of a node are skipped when fn returns
+// false for it. Nil nodes, including typed nil pointers left behind by parse errors, are not visited.
+func Inspect(node Node, fn func(Node) bool) {
+	if node == nil || reflect.ValueOf(node).IsNil() || !fn(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, statement := range node.Statements {
+			Inspect(statement, fn)
+		}
+	case *BlockStatement:
+		for _, statement := range node.Statements {
+			Inspect(statement, fn)
+		}
+	case *ExpressionStatement:
+		Inspect(node.Expression, fn)
+	case *LetStatement:
+		Inspect(node.Name, fn)
+		Inspect(node.Pattern, fn)
+		Inspect(node.Value, fn)
+	case *ReturnStatement:
+		Inspect(node.ReturnValue, fn)
+	case *Pattern:
+		for _, identifier := range node.Identifiers() {
+			Inspect(identifier, fn)
+		}
+	case *PrefixExpression:
+		Inspect(node.Right, fn)
+	case *InfixExpression:
+		Inspect(node.Left, fn)
+		Inspect(node.Right, fn)
+	case *IfExpression:
+		Inspect(node.Condition, fn)
+		Inspect(node.Consequence, fn)
+		Inspect(node.Alternative, fn)
+	case *RangeExpression:
+		Inspect(node.Lower, fn)
+		Inspect(node.Upper, fn)
+		Inspect(node.Step, fn)
+	case *TernaryExpression:
+		Inspect(node.Condition, fn)
+		Inspect(node.Consequence, fn)
+		Inspect(node.Alternative, fn)
+	case *SwitchExpression:
+		Inspect(node.Subject, fn)
+		for _, switchCase := range node.Cases {
+			for _, value := range switchCase.Values {
+				Inspect(value, fn)
+			}
+			Inspect(switchCase.Body, fn)
+		}
+	case *ForStatement:
+		Inspect(node.Init, fn)
+		Inspect(node.Condition, fn)
+		Inspect(node.Post, fn)
+		Inspect(node.Body, fn)
+	case *ForInStatement:
+		Inspect(node.Value, fn)
+		Inspect(node.Index, fn)
+		Inspect(node.Iterable, fn)
+		Inspect(node.Body, fn)
+	case *FunctionLiteralExpression:
+		inspectTypeParameters(node.TypeParameters, fn)
+		for _, parameter := range node.Parameters {
+			Inspect(parameter.Name, fn)
+			Inspect(parameter.Type, fn)
+			Inspect(parameter.Default, fn)
+		}
+		inspectClauses(node.Requires, fn)
+		inspectClauses(node.Ensures, fn)
+		Inspect(node.Body, fn)
+	case *CallExpression:
+		Inspect(node.Function, fn)
+		for _, argument := range node.Arguments {
+			Inspect(argument, fn)
+		}
+	case *ArrayLiteral:
+		for _, element := range node.Elements {
+			Inspect(element, fn)
+		}
+		for _, clause := range node.Clauses {
+			Inspect(clause, fn)
+		}
+	case *ComprehensionClause:
+		Inspect(node.Value, fn)
+		Inspect(node.Index, fn)
+		Inspect(node.Iterable, fn)
+		Inspect(node.Condition, fn)
+	case *MapLiteral:
+		for _, pair := range node.Pairs {
+			Inspect(pair.Key, fn)
+			Inspect(pair.Value, fn)
+		}
+	case *TypeStatement:
+		Inspect(node.Name, fn)
+		inspectTypeParameters(node.TypeParameters, fn)
+		Inspect(node.Type, fn)
+	case *StructType:
+		for _, field := range node.Fields {
+			Inspect(field.Name, fn)
+			Inspect(field.Type, fn)
+		}
+		for _, method := range node.Methods {
+			Inspect(method.Name, fn)
+			Inspect(method.Function, fn)
+		}
+		inspectClauses(node.Invariants, fn)
+	case *InterfaceType:
+		for _, method := range node.Methods {
+			Inspect(method.Name, fn)
+			for _, parameter := range method.Parameters {
+				Inspect(parameter.Name, fn)
+				Inspect(parameter.Type, fn)
+			}
+		}
+	case *StructLiteral:
+		Inspect(node.Type, fn)
+		for _, field := range node.Fields {
+			Inspect(field.Name, fn)
+			Inspect(field.Value, fn)
+		}
+	case *FieldAccessExpression:
+		Inspect(node.Left, fn)
+		Inspect(node.Field, fn)
+	case *IndexExpression:
+		Inspect(node.Left, fn)
+		Inspect(node.Index, fn)
+	case *SliceLiteral:
+		Inspect(node.Left, fn)
+		if node.Lower != nil {
+			Inspect(*node.Lower, fn)
+		}
+		if node.Upper != nil {
+			Inspect(*node.Upper, fn)
+		}
+		if node.Step != nil {
+			Inspect(*node.Step, fn)
+		}
+	case *StringLiteral:
+		for link := &node.StringParts; link.Value != nil; link = link.Next() {
+			if link.Value.Expr != nil {
+				Inspect(link.Value.Expr, fn)
+			}
+			if !link.HasNext() {
+				break
+			}
+		}
+	}
+}
+
+func inspectTypeParameters(typeParameters []*TypeParameter, fn func(Node) bool) {
+	for _, tp := range typeParameters {
+		Inspect(tp.Name, fn)
+		Inspect(tp.Constraint, fn)
+	}
+}
+
+func inspectClauses(clauses []*Clause, fn func(Node) bool) {
+	for _, clause := range clauses {
+		Inspect(clause.Condition, fn)
+	}
+}
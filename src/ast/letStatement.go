@@ -6,9 +6,10 @@ import (
 )
 
 type LetStatement struct {
-	Token token.Token
-	Name  *IdentifierLiteral
-	Value Expression
+	Token   token.Token
+	Name    *IdentifierLiteral // nil when the let destructures a pattern
+	Pattern *Pattern           // nil unless the let destructures the value, e.g. let a, b = f()
+	Value   Expression
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -18,7 +19,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -30,3 +35,12 @@ func (ls *LetStatement) String() string {
 
 	return out.String()
 }
+
+// Names returns the names declared by the let, those of its pattern when it destructures the value
+func (ls *LetStatement) Names() []*IdentifierLiteral {
+	if ls.Pattern != nil {
+		return ls.Pattern.Identifiers()
+	}
+
+	return []*IdentifierLiteral{ls.Name}
+}
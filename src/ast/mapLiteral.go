@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// MapLiteral holds the pairs in source order, e.g. {"a": 1, b: 2} where b is a variable holding the key
+type MapLiteral struct {
+	Token token.Token
+	Pairs []*MapLiteralPair
+}
+
+type MapLiteralPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (m *MapLiteral) expressionNode() {}
+
+func (m *MapLiteral) TokenLiteral() string {
+	return m.Token.Literal
+}
+
+func (m *MapLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := slice.Map(m.Pairs, func(pair *MapLiteralPair) string {
+		return pair.Key.String() + ": " + pair.Value.String()
+	})
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,49 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// Pattern destructures an array into names, e.g. let a, b = f() or let [head, ...rest] = xs. The blank identifier
+// discards an element, the rest takes the elements following the named ones.
+type Pattern struct {
+	Token    token.Token // the first token of the pattern, [ when it is bracketed
+	Names    []*IdentifierLiteral
+	Rest     *IdentifierLiteral // nil when the pattern takes exactly as many elements as it has names
+	Brackets bool
+}
+
+func (p *Pattern) expressionNode()      {}
+func (p *Pattern) TokenLiteral() string { return p.Token.Literal }
+
+func (p *Pattern) String() string {
+	var out bytes.Buffer
+
+	names := slice.Map(p.Names, func(name *IdentifierLiteral) string { return name.String() })
+	if p.Rest != nil {
+		names = append(names, "..."+p.Rest.String())
+	}
+
+	if p.Brackets {
+		out.WriteString("[")
+	}
+	out.WriteString(strings.Join(names, ", "))
+	if p.Brackets {
+		out.WriteString("]")
+	}
+
+	return out.String()
+}
+
+// Identifiers returns the names followed by the rest, the blank identifiers included
+func (p *Pattern) Identifiers() []*IdentifierLiteral {
+	if p.Rest == nil {
+		return p.Names
+	}
+
+	return append(p.Names[:len(p.Names):len(p.Names)], p.Rest)
+}
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"bytes"
+
+	"Flow/src/token"
+)
+
+// RangeExpression is a sequence of integers from Lower to Upper, e.g. 0..10, 0..<10 leaves out the upper bound and
+// 0..10 step 2 takes every second integer
+type RangeExpression struct {
+	Token     token.Token // the .. or ..< token
+	Lower     Expression
+	Upper     Expression
+	Exclusive bool
+	Step      Expression // nil when the step is omitted
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Lower.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.Upper.String())
+	if re.Step != nil {
+		out.WriteString(" step ")
+		out.WriteString(re.Step.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
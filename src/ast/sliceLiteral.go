@@ -6,11 +6,14 @@ import (
 	"Flow/src/token"
 )
 
+// SliceLiteral slices an array from Lower up to Upper taking every Step-th element, e.g. xs[1:-1:2]. Negative bounds
+// count from the end and a negative step slices backwards, omitted bounds span the whole array.
 type SliceLiteral struct {
 	Token token.Token
 	Left  Expression
 	Lower *Expression
 	Upper *Expression
+	Step  *Expression // nil when omitted, the step is 1 then
 }
 
 func (s *SliceLiteral) expressionNode() {}
@@ -31,6 +34,10 @@ func (s *SliceLiteral) String() string {
 		upperStr = (*(*s).Upper).String()
 	}
 
+	if s.Step != nil {
+		return fmt.Sprintf("(%s[%s:%s:%s])", s.Left.String(), lowerStr, upperStr, (*s.Step).String())
+	}
+
 	if s.Lower != nil && s.Upper != nil {
 		return fmt.Sprintf("(%s[%s:%s])", s.Left.String(), lowerStr, upperStr)
 	} else if s.Lower != nil {
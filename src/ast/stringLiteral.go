@@ -2,38 +2,62 @@ package ast
 
 import (
 	"bytes"
+	"strings"
 
 	"Flow/src/token"
+	"Flow/src/utility/escape"
 	"Flow/src/utility/linkedList"
 )
 
 type StringLiteralPart struct {
-	CharacterString *string
-	Expr            *ExpressionStatement
+	CharacterString *string              // unescaped characters
+	Expr            *ExpressionStatement // template expression preceding the characters
+	Interpolation   token.Type           // opening token of the template, either ${ or <
 }
 
+// StringLiteralStyle is the source form of a string literal
+type StringLiteralStyle int
+
+const (
+	QuotedString StringLiteralStyle = iota // "...", holds escape sequences
+	RawString                              // """...""", holds no escape sequences and can span multiple lines
+)
+
 type StringLiteral struct {
 	Token       token.Token
 	StringParts linkedList.LinkedList[StringLiteralPart]
+	Style       StringLiteralStyle
 }
 
 func (s *StringLiteral) expressionNode()      {}
 func (s *StringLiteral) TokenLiteral() string { return s.Token.Literal }
+
+// String returns the string literal as source, characters are escaped again and templates keep their original form
 func (s *StringLiteral) String() string {
+	if s.Style == RawString {
+		return s.rawString()
+	}
+
 	var out bytes.Buffer
 
+	out.WriteString("\"")
+
 	link := s.StringParts
 
 	for {
 		stringLiteralPart := link.Value
 		str, expr := stringLiteralPart.CharacterString, stringLiteralPart.Expr
 
-		if str != nil {
-			out.WriteString(*str)
+		if expr != nil {
+			if stringLiteralPart.Interpolation == token.STRING_INTERPOLATION_OPEN {
+				out.WriteString("<" + expr.String() + ">")
+			} else {
+				out.WriteString("${" + expr.String() + "}")
+			}
 		}
 
-		if expr != nil {
-			out.WriteString(expr.String())
+		if str != nil {
+			out.WriteString(escape.Escape(*str))
 		}
 
 		if link.HasNext() {
@@ -43,5 +67,33 @@ func (s *StringLiteral) String() string {
 		}
 	}
 
+	out.WriteString("\"")
+
 	return out.String()
 }
+
+// rawString returns the raw string literal as source, multi-line content is placed between delimiters on their own
+// lines so parsing the result strips no indentation and yields the same content
+func (s *StringLiteral) rawString() string {
+	var content bytes.Buffer
+
+	for link := &s.StringParts; link != nil; {
+		if expr := link.Value.Expr; expr != nil {
+			content.WriteString("${" + expr.String() + "}")
+		}
+		if str := link.Value.CharacterString; str != nil {
+			content.WriteString(*str)
+		}
+
+		if !link.HasNext() {
+			break
+		}
+		link = link.Next()
+	}
+
+	if strings.Contains(content.String(), "\n") {
+		return "\"\"\"\n" + content.String() + "\n\"\"\""
+	}
+
+	return "\"\"\"" + content.String() + "\"\"\""
+}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// StructLiteral creates a struct of the named type, fields which are not listed are null, e.g. Point{x: 1}
+type StructLiteral struct {
+	Token  token.Token
+	Type   *IdentifierLiteral
+	Fields []*StructLiteralField
+}
+
+type StructLiteralField struct {
+	Name  *IdentifierLiteral
+	Value Expression
+}
+
+func (sl *StructLiteral) expressionNode()      {}
+func (sl *StructLiteral) TokenLiteral() string { return sl.Token.Literal }
+
+func (sl *StructLiteral) String() string {
+	var out bytes.Buffer
+
+	fields := slice.Map(sl.Fields, func(field *StructLiteralField) string {
+		return field.Name.String() + ": " + field.Value.String()
+	})
+
+	out.WriteString(sl.Type.String())
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// HasField reports whether the literal lists the field
+func (sl *StructLiteral) HasField(name string) bool {
+	for _, field := range sl.Fields {
+		if field.Name.Value == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FieldAccessExpression selects a field or method of a struct, e.g. p.x, or p?.x which is null when p is null
+type FieldAccessExpression struct {
+	Token token.Token
+	Left  Expression
+	Field *IdentifierLiteral
+}
+
+func (fa *FieldAccessExpression) expressionNode()      {}
+func (fa *FieldAccessExpression) TokenLiteral() string { return fa.Token.Literal }
+
+func (fa *FieldAccessExpression) String() string {
+	return fa.Left.String() + fa.Token.Literal + fa.Field.String()
+}
+
+// IsSafe reports whether the access is a safe access ?. which doesn't fail on null
+func (fa *FieldAccessExpression) IsSafe() bool { return fa.Token.Type == token.SAFE_DOT }
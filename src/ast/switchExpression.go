@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// SwitchExpression matches the subject against the case values, without subject the case values are predicates
+type SwitchExpression struct {
+	Token   token.Token
+	Subject Expression // nil for a predicate switch
+	Cases   []*SwitchCase
+}
+
+type SwitchCase struct {
+	Token  token.Token
+	Values []Expression // nil for the default case
+	Body   *BlockStatement
+}
+
+func (se *SwitchExpression) expressionNode()      {}
+func (se *SwitchExpression) TokenLiteral() string { return se.Token.Literal }
+
+func (se *SwitchExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("switch ")
+	if se.Subject != nil {
+		out.WriteString(se.Subject.String())
+		out.WriteString(" ")
+	}
+
+	out.WriteString("{")
+	for _, c := range se.Cases {
+		out.WriteString(c.String())
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (sc *SwitchCase) IsDefault() bool { return sc.Values == nil }
+
+func (sc *SwitchCase) String() string {
+	if sc.IsDefault() {
+		return "default: " + sc.Body.String()
+	}
+
+	values := slice.Map(sc.Values, func(value Expression) string {
+		return value.String()
+	})
+
+	return "case " + strings.Join(values, ", ") + ": " + sc.Body.String()
+}
@@ -0,0 +1,166 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// TypeStatement declares a named type, e.g. type Point struct { x int }
+type TypeStatement struct {
+	Token          token.Token
+	Name           *IdentifierLiteral
+	TypeParameters []*TypeParameter // empty unless the type is generic, e.g. type Box<T> struct { value T }
+	Type           Expression
+}
+
+func (ts *TypeStatement) statementNode()       {}
+func (ts *TypeStatement) TokenLiteral() string { return ts.Token.Literal }
+
+func (ts *TypeStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Name.String() + TypeParameterList(ts.TypeParameters) + " " + ts.Type.String()
+}
+
+// StructType lists the fields, methods and invariants of a struct, inside the methods and invariants self refers to
+// the receiving struct
+type StructType struct {
+	Token      token.Token
+	Fields     []*StructField
+	Methods    []*StructMethod
+	Invariants []*Clause
+}
+
+type StructField struct {
+	Name     *IdentifierLiteral
+	Type     *IdentifierLiteral // nil when the type is omitted
+	Nullable bool               // the type is followed by ?, only a nullable type accepts null
+}
+
+type StructMethod struct {
+	Name     *IdentifierLiteral
+	Function *FunctionLiteralExpression
+}
+
+func (st *StructType) expressionNode()      {}
+func (st *StructType) TokenLiteral() string { return st.Token.Literal }
+
+func (st *StructType) String() string {
+	var out bytes.Buffer
+
+	members := slice.Map(st.Fields, func(field *StructField) string {
+		return field.String()
+	})
+	members = append(members, slice.Map(st.Methods, func(method *StructMethod) string {
+		return method.String()
+	})...)
+	members = append(members, slice.Map(st.Invariants, func(invariant *Clause) string {
+		return invariant.String()
+	})...)
+
+	out.WriteString("struct {")
+	out.WriteString(strings.Join(members, "; "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (sf *StructField) String() string {
+	if sf.Type == nil {
+		return sf.Name.String()
+	}
+
+	return sf.Name.String() + " " + typeString(sf.Type, sf.Nullable)
+}
+
+func (sm *StructMethod) String() string {
+	function := sm.Function
+
+	return sm.Name.String() + parameterList(function.Parameters) + clauseList(function.Requires, function.Ensures) + " => " + function.Body.String()
+}
+
+// InterfaceType lists the method signatures a type has to implement to conform to the interface, conformance is
+// structural so a type doesn't declare the interfaces it implements
+type InterfaceType struct {
+	Token   token.Token
+	Methods []*Signature
+}
+
+func (it *InterfaceType) expressionNode()      {}
+func (it *InterfaceType) TokenLiteral() string { return it.Token.Literal }
+
+func (it *InterfaceType) String() string {
+	methods := slice.Map(it.Methods, func(method *Signature) string {
+		return method.String()
+	})
+
+	return "interface {" + strings.Join(methods, "; ") + "}"
+}
+
+// Signature is the name, parameters and return type of a method required by an interface
+type Signature struct {
+	Name           *IdentifierLiteral
+	Parameters     []*Parameter
+	ReturnType     *IdentifierLiteral // nil when the return type is omitted, e.g. area() int
+	ReturnNullable bool
+}
+
+func (s *Signature) String() string {
+	if s.ReturnType != nil {
+		return s.Name.String() + parameterList(s.Parameters) + " " + typeString(s.ReturnType, s.ReturnNullable)
+	}
+
+	return s.Name.String() + parameterList(s.Parameters)
+}
+
+// Accepts reports whether a method with the given signature implements the signature, the number of parameters has
+// to match and so do the types of the parameters and the return types, including whether they are nullable, where
+// both declare one
+func (s *Signature) Accepts(method *Signature) bool {
+	if len(method.Parameters) != len(s.Parameters) {
+		return false
+	}
+
+	for i, parameter := range method.Parameters {
+		if !sameType(parameter.Type, parameter.Nullable, s.Parameters[i].Type, s.Parameters[i].Nullable) {
+			return false
+		}
+	}
+
+	return sameType(method.ReturnType, method.ReturnNullable, s.ReturnType, s.ReturnNullable)
+}
+
+func sameType(typeName *IdentifierLiteral, nullable bool, expected *IdentifierLiteral, expectedNullable bool) bool {
+	if typeName == nil || expected == nil {
+		return true
+	}
+
+	return typeName.Value == expected.Value && nullable == expectedNullable
+}
+
+// Implements describes the first signature the methods don't implement, the methods map their names to their
+// signatures. The description is empty when all signatures are implemented.
+func Implements(signatures []*Signature, methods map[string]*Signature) string {
+	for _, signature := range signatures {
+		method, ok := methods[signature.Name.Value]
+		if !ok {
+			return fmt.Sprintf("missing method %s", signature)
+		}
+
+		if !signature.Accepts(method) {
+			return fmt.Sprintf("method %s has signature %s, expected %s", signature.Name, method, signature)
+		}
+	}
+
+	return ""
+}
+
+func parameterList(parameters []*Parameter) string {
+	params := slice.Map(parameters, func(param *Parameter) string {
+		return param.String()
+	})
+
+	return "(" + strings.Join(params, ", ") + ")"
+}
@@ -11,6 +11,7 @@ import (
 type ParseError interface {
 	error
 	parseError() // to discriminate ParseError from other Errors
+	Token() *token.Token
 	baseErrorInterface
 }
 
@@ -50,6 +51,40 @@ func ParseIntegerLiteralError(tok *token.Token) ParseError {
 	return newParseError(msg, tok)
 }
 
+func InvalidEscapeSequenceError(tok *token.Token, reason string) ParseError {
+	msg := fmt.Sprintf("invalid string literal %q, %s", tok.Literal, reason)
+	return newParseError(msg, tok)
+}
+
+func BlankIdentifierError(tok *token.Token) ParseError {
+	msg := fmt.Sprintf("cannot use %q as value", tok.Literal)
+	return newParseError(msg, tok)
+}
+
+func DuplicateDefaultCaseError(tok *token.Token) ParseError {
+	return newParseError("multiple defaults in switch", tok)
+}
+
+func MisplacedLoopControlError(tok *token.Token) ParseError {
+	msg := fmt.Sprintf("%s is not in a loop", tok.Literal)
+	return newParseError(msg, tok)
+}
+
+func DuplicateMemberError(tok *token.Token) ParseError {
+	msg := fmt.Sprintf("duplicate member %q", tok.Literal)
+	return newParseError(msg, tok)
+}
+
+func DuplicateTypeParameterError(tok *token.Token) ParseError {
+	msg := fmt.Sprintf("duplicate type parameter %q", tok.Literal)
+	return newParseError(msg, tok)
+}
+
+func UnknownTypeKindError(tok *token.Token) ParseError {
+	msg := fmt.Sprintf("expected struct or interface, got %q instead", tok.Literal)
+	return newParseError(msg, tok)
+}
+
 func newParseError(msg string, context *token.Token) *parseError {
 	return &parseError{
 		&tokenError{
@@ -57,3 +92,8 @@ func newParseError(msg string, context *token.Token) *parseError {
 		},
 	}
 }
+
+func InvalidParameterError(tok *token.Token, reason string) ParseError {
+	msg := fmt.Sprintf("invalid parameter %q, %s", tok.Literal, reason)
+	return newParseError(msg, tok)
+}
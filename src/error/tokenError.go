@@ -11,6 +11,11 @@ type tokenError struct {
 	context *token.Token
 }
 
+// Token returns the token the error is positioned at
+func (t *tokenError) Token() *token.Token {
+	return t.context
+}
+
 func (t *tokenError) Error() string {
 	fContext := fmt.Sprintf("%d:%d", t.context.Line, t.context.Pos)
 	return fmt.Sprintf("%s: %s", fContext, t.err)
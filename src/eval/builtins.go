@@ -0,0 +1,98 @@
+package eval
+
+import (
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+)
+
+// the builtins calling functions are registered here since they call them like the evaluator does
+func init() {
+	object.Builtins["filter"] = &object.NativeFunc{Call: filter, Arity: 2}
+	object.Builtins["reduce"] = &object.NativeFunc{Call: reduce, Arity: 3}
+}
+
+// filter returns an array of the values the predicate holds for, it is called with the value and its index or key and
+// may leave the index out, e.g. filter((x) => x > 1, [1, 2, 3]) evaluates to [2, 3]
+func filter(tok token.Token, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return object.NewEvalErrorObject("%sexpected 2 argument(s) for filter got=%d", tokenToPos(tok), len(args))
+	}
+
+	predicate, values := args[0], args[1]
+	next := iterate(values)
+	if next == nil {
+		return object.NewEvalErrorObject("%sargument 2 to \"filter\" must be iterable, got=%s", tokenToPos(tok), values.Type())
+	}
+
+	elements := []object.Object{}
+	for element, index, ok := next(); ok; element, index, ok = next() {
+		held := callFunction(predicate, callbackArguments(predicate, element, index), tok)
+		if isError(held) {
+			return held
+		}
+
+		switch held {
+		case object.TRUE:
+			elements = append(elements, element)
+		case object.FALSE:
+		default:
+			return object.NewEvalErrorObject("%spredicate of filter must return BOOLEAN, got=%s", tokenToPos(tok), held.Type())
+		}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// reduce folds the values into the initial one, the reducer is called with the accumulated value, the value and its
+// index or key and may leave the index out, e.g. reduce((acc, x) => acc + x, 0, [1, 2, 3]) evaluates to 6
+func reduce(tok token.Token, args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return object.NewEvalErrorObject("%sexpected 3 argument(s) for reduce got=%d", tokenToPos(tok), len(args))
+	}
+
+	reducer, acc, values := args[0], args[1], args[2]
+	next := iterate(values)
+	if next == nil {
+		return object.NewEvalErrorObject("%sargument 3 to \"reduce\" must be iterable, got=%s", tokenToPos(tok), values.Type())
+	}
+
+	for element, index, ok := next(); ok; element, index, ok = next() {
+		acc = callFunction(reducer, callbackArguments(reducer, acc, element, index), tok)
+		if isError(acc) {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+// callbackArguments returns the longest prefix of the offered arguments the function takes, all of them when it takes
+// none so calling it reports the mismatch
+func callbackArguments(fn object.Object, offered ...object.Object) []object.Object {
+	for count := len(offered); count > 0; count-- {
+		if takesArguments(fn, count) {
+			return offered[:count]
+		}
+	}
+
+	return offered
+}
+
+func takesArguments(fn object.Object, count int) bool {
+	switch fn := fn.(type) {
+	case *object.Function:
+		return ast.TakesArguments(fn.Parameters, count)
+	case *object.Overloads:
+		for _, overload := range fn.Functions {
+			if ast.TakesArguments(overload.Parameters, count) {
+				return true
+			}
+		}
+		return false
+	case *object.NativeFunc:
+		return fn.Arity == 0 || fn.Arity == count
+	}
+
+	return false
+}
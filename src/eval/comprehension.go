@@ -0,0 +1,75 @@
+package eval
+
+import (
+	"Flow/src/ast"
+	"Flow/src/object"
+)
+
+// isComprehension reports whether the expression builds an array from clauses, the array is stored evaluated so it is
+// built once where it is bound and its elements can be assigned like the ones of any evaluated array
+func isComprehension(expr ast.Expression) bool {
+	array, ok := expr.(*ast.ArrayLiteral)
+	return ok && array.Generator
+}
+
+// evalComprehension builds the array of a comprehension in a single pass, the element is evaluated and appended for
+// every iteration of the innermost clause without building arrays for the clauses in between
+func evalComprehension(node *ast.ArrayLiteral, env *object.Environment) object.Object {
+	array := &object.Array{Elements: []object.Object{}}
+
+	if err := evalClauses(node, node.Clauses, array, env); err != nil {
+		return err
+	}
+
+	return array
+}
+
+// evalClauses evaluates the first of the clauses and the ones following it nested inside, each iteration of a for clause
+// binds its names in an environment enclosing the one of the clause
+func evalClauses(node *ast.ArrayLiteral, clauses []*ast.ComprehensionClause, array *object.Array, env *object.Environment) object.Object {
+	if len(clauses) == 0 {
+		element := Eval(node.Elements[0], env)
+		if isError(element) {
+			return element
+		}
+		array.Elements = append(array.Elements, element)
+		return nil
+	}
+
+	clause := clauses[0]
+
+	if clause.Condition != nil {
+		condition := Eval(clause.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return nil
+		}
+		return evalClauses(node, clauses[1:], array, env)
+	}
+
+	iterable := Eval(clause.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	next := iterate(iterable)
+	if next == nil {
+		return object.NewEvalErrorObject("%scannot iterate over %s", tokenToPos(clause.Token), iterable.Type())
+	}
+
+	for element, index, ok := next(); ok; element, index, ok = next() {
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		bindValue(iterationEnv, clause.Value, element)
+		if clause.Index != nil {
+			bindValue(iterationEnv, clause.Index, index)
+		}
+
+		if err := evalClauses(node, clauses[1:], array, iterationEnv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package eval
+
+import (
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+)
+
+// CheckContracts turns the checks of require, ensure and invariant clauses on, they cost an evaluation of every clause
+// so flow run -contracts=false turns them off
+var CheckContracts = true
+
+// checkClauses evaluates the clauses in the environment, the first clause which doesn't hold is returned as
+// ContractError positioned at the call or assignment checking it
+func checkClauses(clauses []*ast.Clause, env *object.Environment, tok token.Token) object.Object {
+	if !CheckContracts {
+		return nil
+	}
+
+	for _, clause := range clauses {
+		holds := Eval(clause.Condition, env)
+		if isError(holds) {
+			return holds
+		}
+
+		if !isTruthy(holds) {
+			return &object.ContractError{Clause: clause.Source, Line: tok.Line, Pos: tok.Pos}
+		}
+	}
+
+	return nil
+}
+
+// snapshotParameters evaluates the parameters before the body runs, the ensure clauses refer to them as old.name. It is
+// nil when there are no ensure clauses to check. Arrays, maps and structs are copied so old keeps their values from
+// before the body changed them.
+func snapshotParameters(fn *object.Function, env *object.Environment) (*object.Struct, object.Object) {
+	if !CheckContracts || len(fn.Ensures) == 0 {
+		return nil, nil
+	}
+
+	structType := &object.StructType{Name: "old", FieldTypes: make(map[string]object.FieldType)}
+	values := make(map[string]object.Object)
+	copies := make(map[object.Object]object.Object)
+
+	for _, param := range fn.Parameters {
+		if param.Name.IsBlank() {
+			continue
+		}
+
+		value := Eval(param.Name, env)
+		if isError(value) {
+			return nil, value
+		}
+
+		structType.Fields = append(structType.Fields, param.Name.Value)
+		values[param.Name.Value] = snapshot(value, copies)
+	}
+
+	old := object.NewStruct(structType)
+	for name, value := range values {
+		old.Set(name, value)
+	}
+
+	return old, nil
+}
+
+// snapshot copies arrays, maps and structs with the values they hold, other values can't change so they are returned
+// as they are. A value referenced several times is copied once so the copies reference each other the same way.
+func snapshot(value object.Object, copies map[object.Object]object.Object) object.Object {
+	switch value := value.(type) {
+	case *object.Array:
+		if c, ok := copies[value]; ok {
+			return c
+		}
+		c := &object.Array{Elements: make([]object.Object, len(value.Elements))}
+		copies[value] = c
+		for i, element := range value.Elements {
+			c.Elements[i] = snapshot(element, copies)
+		}
+		return c
+	case *object.Map:
+		if c, ok := copies[value]; ok {
+			return c
+		}
+		c := object.NewMap()
+		copies[value] = c
+		for _, pair := range value.Pairs() {
+			c.Set(pair.Key, snapshot(pair.Value, copies))
+		}
+		return c
+	case *object.Struct:
+		if c, ok := copies[value]; ok {
+			return c
+		}
+		c := object.NewStruct(value.StructType)
+		copies[value] = c
+		for _, name := range value.StructType.Fields {
+			field, _ := value.Get(name)
+			c.Set(name, snapshot(field, copies))
+		}
+		return c
+	default:
+		return value
+	}
+}
+
+// ensureEnvironment encloses the environment of the call binding result to the returned value and old to the
+// parameters as they were before the body ran
+func ensureEnvironment(env *object.Environment, result object.Object, old *object.Struct) *object.Environment {
+	if old == nil {
+		return env
+	}
+
+	ensureEnv := object.NewEnclosedEnvironment(env)
+	bindValue(ensureEnv, &ast.IdentifierLiteral{Value: "result"}, result)
+	bindValue(ensureEnv, &ast.IdentifierLiteral{Value: "old"}, old)
+
+	return ensureEnv
+}
+
+// checkInvariants checks the invariants of the struct after it is created or one of its fields is assigned, self
+// refers to the struct
+func checkInvariants(s *object.Struct, tok token.Token) object.Object {
+	if len(s.StructType.Invariants) == 0 {
+		return nil
+	}
+
+	return checkClauses(s.StructType.Invariants, selfEnvironment(s.StructType.Env, s), tok)
+}
@@ -3,12 +3,14 @@ package eval
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strconv"
+	"unicode/utf8"
 
 	"Flow/src/ast"
 	"Flow/src/object"
+	_ "Flow/src/stdlib" // registers the modules of the standard library
 	"Flow/src/token"
-	"Flow/src/utility/slice"
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
@@ -30,6 +32,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalBlockStatement(node, env)
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
+		if interrupts(right) {
+			return right
+		}
 		return evalPrefixExpression(node.Operator, right, node.Token)
 	case *ast.InfixExpression:
 		return evalInfixExpression(node, env)
@@ -37,35 +42,80 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return &object.Integer{Value: node.Value}
 	case *ast.BooleanLiteral:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return object.NULL
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.TernaryExpression:
+		return evalTernaryExpression(node, env)
+	case *ast.SwitchExpression:
+		return evalSwitchExpression(node, env)
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
+		if interrupts(val) {
+			return val
+		}
 		return &object.ReturnValue{Value: val}
 	case *ast.LetStatement:
 		return evalLetExpression(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.ForInStatement:
+		return evalForInStatement(node, env)
+	case *ast.BreakStatement:
+		return object.BREAK
+	case *ast.ContinueStatement:
+		return object.CONTINUE
+	case *ast.EvaluatedExpression:
+		return node.Value.(object.Object)
 	case *ast.IdentifierLiteral:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteralExpression:
-		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+		return &object.Function{
+			TypeParameters: node.TypeParameters,
+			Parameters:     node.Parameters,
+			ReturnType:     node.ReturnType,
+			ReturnNullable: node.ReturnNullable,
+			Requires:       node.Requires,
+			Ensures:        node.Ensures,
+			Body:           node.Body,
+			Env:            env,
+		}
 	case *ast.CallExpression:
 		fn := Eval(node.Function, env)
-		if isError(fn) {
+		if interrupts(fn) {
 			return fn
 		}
-		return applyFunction(fn, node.Arguments, env)
+		// calling a method through a safe access of null, e.g. p?.sum(), evaluates to null
+		if fieldAccess, ok := node.Function.(*ast.FieldAccessExpression); ok && fieldAccess.IsSafe() && fn == object.NULL {
+			return object.NULL
+		}
+		return applyFunction(fn, node.Arguments, env, node.Token)
 	case *ast.StringLiteral:
 		return evalStringLiteral(node, env)
 	case *ast.ArrayLiteral:
+		if node.Generator {
+			return evalComprehension(node, env)
+		}
 		elements := evalExpressions(node.Elements, env)
-		if len(elements) == 1 && isError(elements[0]) {
+		if len(elements) == 1 && interrupts(elements[0]) {
 			return elements[0]
 		}
 		return &object.Array{Elements: elements}
+	case *ast.MapLiteral:
+		return evalMapLiteral(node, env)
+	case *ast.TypeStatement:
+		return evalTypeStatement(node, env)
+	case *ast.StructLiteral:
+		return evalStructLiteral(node, env)
+	case *ast.FieldAccessExpression:
+		return evalFieldAccessExpression(node, env)
 	case *ast.IndexExpression:
 		return evalIndexExpression(node, env)
 	case *ast.SliceLiteral:
 		return evalSliceExpression(node, env)
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
 	}
 
 	return nil
@@ -92,7 +142,7 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 		switch result := result.(type) {
 		case *object.ReturnValue:
 			return result.Value
-		case *object.EvalError:
+		case *object.EvalError, *object.ContractError:
 			return result
 		}
 	}
@@ -106,20 +156,178 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 	for _, statement := range block.Statements {
 		result = Eval(statement, env)
 
-		if result != nil && result.Type() == object.RETURN_VALUE_OBJ {
-			return result
+		if result != nil {
+			switch result.Type() {
+			case object.RETURN_VALUE_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ, object.ERROR_OBJ, object.CONTRACT_ERROR_OBJ:
+				return result
+			}
 		}
 	}
 
 	return result
 }
 
+// evalForStatement runs the loop in its own environment holding the variables of the init statement, every iteration
+// gets a new environment for the variables declared in the body. A loop is a statement and evaluates to null.
+func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
+	loopEnv := object.NewEnclosedEnvironment(env)
+
+	if fs.Init != nil {
+		if result := Eval(fs.Init, loopEnv); isError(result) {
+			return result
+		}
+	}
+
+	// every iteration binds its own copy of the variables declared by the init statement, the post statement updates
+	// the copy of the next iteration so closures created by the body keep the values of their iteration
+	names := loopEnv.Names()
+	iterationEnv := loopEnv
+
+	for {
+		if fs.Condition != nil {
+			condition := Eval(fs.Condition, iterationEnv)
+			if isError(condition) {
+				return condition
+			}
+
+			if !isTruthy(condition) {
+				return object.NULL
+			}
+		}
+
+		if result, done := evalLoopBody(fs.Body, object.NewEnclosedEnvironment(iterationEnv)); done {
+			return result
+		}
+
+		next, err := copyLoopVariables(fs.Token, names, iterationEnv, env)
+		if err != nil {
+			return err
+		}
+		iterationEnv = next
+
+		if fs.Post != nil {
+			if result := Eval(fs.Post, iterationEnv); isError(result) {
+				return result
+			}
+		}
+	}
+}
+
+// copyLoopVariables binds the current values of the loop variables in a new environment enclosing outer
+func copyLoopVariables(tok token.Token, names []string, from, outer *object.Environment) (*object.Environment, object.Object) {
+	to := object.NewEnclosedEnvironment(outer)
+
+	for _, name := range names {
+		identifier := &ast.IdentifierLiteral{Token: tok, Value: name}
+
+		value := evalIdentifier(identifier, from)
+		if isError(value) {
+			return nil, value
+		}
+
+		bindValue(to, identifier, value)
+	}
+
+	return to, nil
+}
+
+// evalForInStatement runs the body for every element of the iterable, the element and index or key are bound as values in an
+// environment enclosing the environment of the body, references are substituted from the outer environment so the
+// variables declared in the body can refer to them
+func evalForInStatement(fs *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	next := iterate(iterable)
+	if next == nil {
+		return object.NewEvalErrorObject("%scannot iterate over %s", tokenToPos(fs.Token), iterable.Type())
+	}
+
+	for element, index, ok := next(); ok; element, index, ok = next() {
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		bindValue(iterationEnv, fs.Value, element)
+		if fs.Index != nil {
+			bindValue(iterationEnv, fs.Index, index)
+		}
+
+		if result, done := evalLoopBody(fs.Body, object.NewEnclosedEnvironment(iterationEnv)); done {
+			return result
+		}
+	}
+
+	return object.NULL
+}
+
+// iterate returns a function yielding the elements of the iterable with their index or key until ok is false, it is nil
+// when the value cannot be iterated over. The elements of a range are computed as they are yielded.
+func iterate(iterable object.Object) func() (element, index object.Object, ok bool) {
+	var i int64
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		return func() (object.Object, object.Object, bool) {
+			if i >= int64(len(iterable.Elements)) {
+				return nil, nil, false
+			}
+			i++
+			return iterable.Elements[i-1], &object.Integer{Value: i - 1}, true
+		}
+	case *object.Map: // the pairs are copied so the body can modify the map
+		pairs := iterable.Pairs()
+		return func() (object.Object, object.Object, bool) {
+			if i >= int64(len(pairs)) {
+				return nil, nil, false
+			}
+			i++
+			return pairs[i-1].Value, pairs[i-1].Key, true
+		}
+	case *object.Range: // a range longer than the largest integer yields as many integers, which never ends in practice
+		length, _ := iterable.Len()
+		return func() (object.Object, object.Object, bool) {
+			if i >= length {
+				return nil, nil, false
+			}
+			i++
+			return iterable.At(i - 1), &object.Integer{Value: i - 1}, true
+		}
+	default:
+		return nil
+	}
+}
+
+// evalLoopBody evaluates a single iteration, done reports whether the loop ends with the returned result. Break ends
+// the loop, return values and errors are passed on to the enclosing block.
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (result object.Object, done bool) {
+	result = Eval(body, env)
+
+	switch result.(type) {
+	case *object.Break:
+		return object.NULL, true
+	case *object.ReturnValue, *object.EvalError, *object.ContractError:
+		return result, true
+	}
+
+	return nil, false
+}
+
+// bindValue sets an evaluated value in the environment, the blank identifier discards the value
+func bindValue(env *object.Environment, identifier *ast.IdentifierLiteral, value object.Object) {
+	if identifier.IsBlank() {
+		return
+	}
+
+	var expr ast.Expression = &ast.EvaluatedExpression{Token: identifier.Token, Value: value}
+	env.Set(identifier.Value, &expr)
+}
+
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
 	var result []object.Object
 
 	for _, e := range exps {
 		evaluated := Eval(e, env)
-		if isError(evaluated) {
+		if interrupts(evaluated) {
 			return []object.Object{evaluated}
 		}
 
@@ -129,30 +337,264 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
-func applyFunction(fn object.Object, args []ast.Expression, env *object.Environment) object.Object {
+// applyFunction evaluates the arguments in the environment of the call and calls the function with them, given fewer
+// arguments than it requires or placeholders it returns the function partially applied
+func applyFunction(fn object.Object, args []ast.Expression, env *object.Environment, tok token.Token) object.Object {
+	switch fn.(type) {
+	case *object.Function, *object.Overloads, *object.NativeFunc:
+	default:
+		return object.NewEvalErrorObject("%snot a function: %s", tokenToPos(tok), fn.Type())
+	}
+
+	values, err := evalArguments(args, env)
+	if err != nil {
+		return err
+	}
+
+	if isPartial(fn, values) {
+		return partiallyApply(fn, values, tok)
+	}
+
+	return callFunction(fn, values, tok)
+}
+
+// callFunction calls the function with the evaluated arguments, the contract of the function is checked at the call
+// positioned at tok
+func callFunction(fn object.Object, args []object.Object, tok token.Token) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		extendedEnv, typeArguments, err := extendFunctionEnv(fn, args, tok)
+		if err != nil {
+			return err
+		}
+
+		if err := checkClauses(fn.Requires, extendedEnv, tok); err != nil {
+			return err
+		}
+
+		old, err := snapshotParameters(fn, extendedEnv)
+		if err != nil {
+			return err
+		}
+
+		evaluated := unwrapReturnValue(Eval(fn.Body, extendedEnv))
+		if isError(evaluated) {
+			return evaluated
+		}
+
+		if fn.ReturnType != nil {
+			if err := checkType(fn, fn.ReturnType, fn.ReturnNullable, evaluated, typeArguments); err != nil {
+				return object.NewEvalErrorObject("%sreturn value: %s", tokenToPos(tok), err)
+			}
+		}
+
+		if err := checkClauses(fn.Ensures, ensureEnvironment(extendedEnv, evaluated, old), tok); err != nil {
+			return err
+		}
+
+		return evaluated
+	case *object.Overloads:
+		selected, err := resolveOverload(fn, args, tok)
+		if err != nil {
+			return err
+		}
+
+		return callFunction(selected, args, tok)
 	case *object.NativeFunc:
-		evaluatedArgs := slice.Map(args, func(expr ast.Expression) object.Object {
-			return Eval(expr, env)
-		})
-		return fn.Fn(evaluatedArgs...)
+		if fn.Call != nil {
+			return fn.Call(tok, args...)
+		}
+		return fn.Fn(args...)
 	default:
-		return object.NewEvalErrorObject("not a function: %s", fn.Type())
+		return object.NewEvalErrorObject("%snot a function: %s", tokenToPos(tok), fn.Type())
 	}
 }
 
-func extendFunctionEnv(fn *object.Function, args []ast.Expression) *object.Environment {
+// extendFunctionEnv binds the arguments to the parameters in an environment enclosing the one the function is declared
+// in, so the body sees the variables of its declaration instead of those of the call. A missing argument binds null to
+// an optional parameter and the default value to a parameter declaring one, it is evaluated after the preceding
+// parameters are bound. A variadic parameter binds the remaining arguments as array. The returned type arguments are
+// those inferred from the arguments.
+func extendFunctionEnv(fn *object.Function, args []object.Object, tok token.Token) (*object.Environment, *object.TypeArguments, object.Object) {
+	if !ast.TakesArguments(fn.Parameters, len(args)) {
+		least, most := ast.Arity(fn.Parameters)
+		return nil, nil, object.NewEvalErrorObject("%swrong number of arguments: expected %s, got %d", tokenToPos(tok), arityString(least, most), len(args))
+	}
+
 	env := object.NewEnclosedEnvironment(fn.Env)
 
-	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, &args[paramIdx])
+	typeArguments, err := callTypeArguments(fn)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return env
+	for i, param := range fn.Parameters {
+		var value object.Object
+
+		switch {
+		case param.Variadic:
+			var rest []object.Object
+			if i < len(args) {
+				rest = args[i:]
+			}
+			value, err = collectVariadic(fn, param, rest, typeArguments)
+			if err != nil {
+				return nil, nil, err
+			}
+			bindValue(env, param.Name, value)
+			continue
+		case i < len(args):
+			value = args[i]
+		case param.Default != nil:
+			// evaluated on each call where the preceding parameters are bound
+			value = Eval(param.Default, env)
+			if isError(value) {
+				return nil, nil, value
+			}
+		default:
+			value = object.NULL
+		}
+
+		if param.Type != nil {
+			if err := checkArgument(fn, param, value, typeArguments); err != nil {
+				return nil, nil, object.NewEvalErrorObject("argument %s: %s", param.Name.Value, err)
+			}
+		}
+
+		bindValue(env, param.Name, value)
+	}
+
+	return env, typeArguments, nil
+}
+
+// collectVariadic collects the remaining arguments of a call into an array, each of them has to match the type of the
+// variadic parameter
+func collectVariadic(fn *object.Function, param *ast.Parameter, args []object.Object, typeArguments *object.TypeArguments) (object.Object, object.Object) {
+	elements := make([]object.Object, len(args))
+	copy(elements, args)
+
+	if param.Type != nil {
+		for _, element := range elements {
+			if err := checkArgument(fn, param, element, typeArguments); err != nil {
+				return nil, object.NewEvalErrorObject("argument %s: %s", param.Name.Value, err)
+			}
+		}
+	}
+
+	return &object.Array{Elements: elements}, nil
+}
+
+// arityString describes the number of arguments a function takes, e.g. 2, 1 to 3 or at least 1
+func arityString(least, most int) string {
+	switch {
+	case most < 0:
+		return fmt.Sprintf("at least %d", least)
+	case least == most:
+		return strconv.Itoa(least)
+	default:
+		return fmt.Sprintf("%d to %d", least, most)
+	}
+}
+
+// checkArgument checks the value passed for a typed parameter, only a nullable type or optional parameter accepts
+// null
+func checkArgument(fn *object.Function, param *ast.Parameter, value object.Object, typeArguments *object.TypeArguments) error {
+	return checkType(fn, param.Type, param.AcceptsNull(), value, typeArguments)
+}
+
+// checkType checks whether the value is of the type named by typeName, types are resolved where the function is
+// declared. A type parameter infers its type argument from the value, an interface has to be implemented by it and
+// builtin and struct types have to match it exactly.
+func checkType(fn *object.Function, typeName *ast.IdentifierLiteral, nullable bool, value object.Object, typeArguments *object.TypeArguments) error {
+	if value == object.NULL {
+		if nullable {
+			return nil
+		}
+		return fmt.Errorf("%s is not nullable", typeName.Value)
+	}
+
+	if typeArguments.IsParameter(typeName.Value) {
+		return typeArguments.Bind(typeName.Value, value)
+	}
+
+	if builtin, ok := object.BuiltinType(typeName.Value); ok {
+		if value.Type() != builtin {
+			return fmt.Errorf("%s is not of type %s", object.TypeName(value), typeName.Value)
+		}
+		return nil
+	}
+
+	if iface, ok := lookupInterface(typeName.Value, fn.Env); ok {
+		return iface.Check(value)
+	}
+
+	if structType, ok := lookupStructType(typeName.Value, fn.Env); ok {
+		if s, ok := value.(*object.Struct); !ok || s.StructType != structType {
+			return fmt.Errorf("%s is not of type %s", object.TypeName(value), typeName.Value)
+		}
+	}
+
+	return nil
+}
+
+// parameterInterface returns the interface the parameter is typed by, types are resolved where the function is declared
+func parameterInterface(fn *object.Function, param *ast.Parameter) (*object.InterfaceType, bool) {
+	if param.Type == nil {
+		return nil, false
+	}
+
+	return lookupInterface(param.Type.Value, fn.Env)
+}
+
+// callTypeArguments returns the type arguments inferring the types of a call of the function, a partially applied
+// function continues from those inferred from its bound arguments
+func callTypeArguments(fn *object.Function) (*object.TypeArguments, object.Object) {
+	if fn.TypeArguments != nil {
+		return fn.TypeArguments.Copy(), nil
+	}
+
+	typeParameters, err := resolveTypeParameters(fn.TypeParameters, fn.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewTypeArguments(typeParameters), nil
+}
+
+// resolveTypeParameters resolves the constraints of the type parameters, they have to name an interface
+func resolveTypeParameters(typeParameters []*ast.TypeParameter, env *object.Environment) ([]*object.TypeParameter, object.Object) {
+	var resolved []*object.TypeParameter
+
+	for _, tp := range typeParameters {
+		typeParameter := &object.TypeParameter{Name: tp.Name.Value}
+
+		if tp.Constraint != nil {
+			constraint, ok := lookupInterface(tp.Constraint.Value, env)
+			if !ok {
+				return nil, object.NewEvalErrorObject("%sconstraint %s of type parameter %s is not an interface", tokenToPos(tp.Constraint.Token), tp.Constraint.Value, tp.Name.Value)
+			}
+			typeParameter.Constraint = constraint
+		}
+
+		resolved = append(resolved, typeParameter)
+	}
+
+	return resolved, nil
+}
+
+func lookupInterface(name string, env *object.Environment) (*object.InterfaceType, bool) {
+	declaration, ok := env.Get(name)
+	if !ok {
+		return nil, false
+	}
+
+	evaluated, ok := (*declaration).(*ast.EvaluatedExpression)
+	if !ok {
+		return nil, false
+	}
+
+	iface, ok := evaluated.Value.(*object.InterfaceType)
+	return iface, ok
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -179,17 +621,31 @@ func evalInfixExpression(node *ast.InfixExpression, env *object.Environment) obj
 		return evalAssignmentExpression(node, env)
 	}
 
+	if node.Operator == "??" {
+		return evalCoalesceExpression(node, env)
+	}
+
 	operator := node.Operator
-	left := *unwrapObservable(Eval(node.Left, env), env)
-	right := *unwrapObservable(Eval(node.Right, env), env)
+	leftValue := Eval(node.Left, env)
+	if interrupts(leftValue) {
+		return leftValue
+	}
+	rightValue := Eval(node.Right, env)
+	if interrupts(rightValue) {
+		return rightValue
+	}
+	left := *unwrapObservable(leftValue, env)
+	right := *unwrapObservable(rightValue, env)
 
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right)
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right) // Only works with booleans because bool objects are reused so memory address matches
+		return nativeBoolToBooleanObject(isEqual(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!isEqual(left, right))
 	case left.Type() != right.Type():
 		return object.NewEvalErrorObject("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
@@ -197,6 +653,21 @@ func evalInfixExpression(node *ast.InfixExpression, env *object.Environment) obj
 	}
 }
 
+// evalCoalesceExpression evaluates to the left value unless it is null, the right side is only evaluated when it is
+func evalCoalesceExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	value := Eval(node.Left, env)
+	if interrupts(value) {
+		return value
+	}
+
+	left := *unwrapObservable(value, env)
+	if left != object.NULL {
+		return left
+	}
+
+	return Eval(node.Right, env)
+}
+
 func unwrapObservable(o object.Object, env *object.Environment) *object.Object {
 	if observable, ok := o.(*object.Observable); ok {
 		val := Eval(*observable.Value, env)
@@ -207,12 +678,16 @@ func unwrapObservable(o object.Object, env *object.Environment) *object.Object {
 }
 
 func evalLetExpression(node *ast.LetStatement, env *object.Environment) object.Object {
+	if node.Pattern != nil {
+		return evalLetPattern(node, env)
+	}
+
 	if sliceLiteral, ok := node.Value.(*ast.SliceLiteral); ok {
 		c := shallowCopySliceLiteral(sliceLiteral, env)
 		node.Value = c
 	}
 	val := Eval(node.Value, env)
-	if isError(val) {
+	if interrupts(val) {
 		return val
 	}
 
@@ -223,7 +698,20 @@ func evalLetExpression(node *ast.LetStatement, env *object.Environment) object.O
 		o.Register(observable)
 	}
 
-	env.Set(node.Name.Value, &node.Value)
+	if node.Name.IsBlank() {
+		return object.NULL
+	}
+
+	if fn, ok := val.(*object.Function); ok && declareOverload(node.Name, fn, env) {
+		return object.NULL
+	}
+
+	if isReference(val) || isComprehension(node.Value) {
+		var evaluated ast.Expression = &ast.EvaluatedExpression{Token: node.Name.Token, Value: val}
+		env.Set(node.Name.Value, &evaluated)
+	} else {
+		env.Set(node.Name.Value, &node.Value)
+	}
 
 	return object.NULL
 }
@@ -234,12 +722,23 @@ func evalAssignmentExpression(node *ast.InfixExpression, env *object.Environment
 		return evalAssignIdentifier(left, &node.Right, env)
 	case *ast.IndexExpression:
 		return evalAssignIndexExpr(left, left.Index, &node.Right, env)
+	case *ast.FieldAccessExpression:
+		return evalAssignField(left, &node.Right, env)
+	case *ast.Pattern:
+		return evalAssignPattern(left, node.Right, env)
 	default:
 		return object.NewEvalErrorObject("can't assign to give type %T", node.Left)
 	}
 }
 
 func evalAssignIdentifier(identifier *ast.IdentifierLiteral, right *ast.Expression, env *object.Environment) object.Object {
+	if identifier.IsBlank() {
+		if val := Eval(*right, env); interrupts(val) {
+			return val
+		}
+		return object.NULL
+	}
+
 	expr, ok := env.Get(identifier.Value)
 	if !ok {
 		return object.NewEvalErrorObject(fmt.Sprintf("identifier not found: %q", identifier.Value))
@@ -250,81 +749,237 @@ func evalAssignIdentifier(identifier *ast.IdentifierLiteral, right *ast.Expressi
 	if observable, ok := val.(*object.Observable); ok {
 		observable.Value = right
 		observable.NotifyAll(right)
-	} else {
-		env.Set(identifier.Value, right)
+		return object.NULL
 	}
 
-	return object.NULL
-}
+	// the variable is updated where it is declared, assignments in loop bodies and functions outlive their environment
+	scope, _ := env.Scope(identifier.Value)
 
-func evalAssignIndexExpr(indexExpr *ast.IndexExpression, index ast.Expression, value *ast.Expression, env *object.Environment) object.Object {
-	var (
-		array    *ast.ArrayLiteral
-		indexInt *ast.IntegerLiteral
-	)
+	value := Eval(*right, env)
+	if interrupts(value) {
+		return value
+	}
 
-	indexInt, ok := index.(*ast.IntegerLiteral)
-	if !ok {
-		return object.NewEvalErrorObject("expected integer for indexing array, got=%T", index)
+	if isReference(value) || isEvaluatedOnAssignment(*right, env, scope) {
+		var evaluated ast.Expression = &ast.EvaluatedExpression{Token: identifier.Token, Value: value}
+		right = &evaluated
 	}
 
-	if array, ok = indexExpr.Left.(*ast.ArrayLiteral); ok { // if index is used on array directly
-		array.Elements[indexInt.Value] = *value
+	scope.Set(identifier.Value, right)
 
-		return object.NULL
-	} else if identifier, ok := indexExpr.Left.(*ast.IdentifierLiteral); ok { // if index is used on identifier referencing array
-		currentValue, ok := env.Get(identifier.Value)
-		if !ok {
-			return object.NewEvalErrorObject(fmt.Sprintf("identifier not found: %q", identifier.Value))
-		}
-		array, ok = (*currentValue).(*ast.ArrayLiteral)
-		if !ok {
-			return object.NewEvalErrorObject(fmt.Sprintf("identifier not array, got=%T", currentValue))
-		}
+	return object.NULL
+}
 
-		array.Elements[indexInt.Value] = *value
-	} else {
-		return object.NewEvalErrorObject("expected array for index expression, got =%T", indexExpr.Left)
+// isReference reports whether the value is mutable, all references to a map or struct, or to an array holding them,
+// have to share the evaluated value so they are stored evaluated instead of as expression. Functions are stored
+// evaluated as well, they capture the environment they are created in.
+func isReference(value object.Object) bool {
+	switch value := value.(type) {
+	case *object.Map, *object.Struct, *object.Function, *object.Overloads:
+		return true
+	case *object.Array:
+		for _, element := range value.Elements {
+			if isReference(element) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
 	}
-
-	return object.NULL
 }
 
-func evalIdentifier(node *ast.IdentifierLiteral, env *object.Environment) object.Object {
-	if expr, ok := env.Get(node.Value); ok {
-		return Eval(*expr, env)
+// evalAssignField assigns to a field of a struct, the observers of the struct are notified of the change
+func evalAssignField(fa *ast.FieldAccessExpression, right *ast.Expression, env *object.Environment) object.Object {
+	s, err := evalStructOperand(fa, env)
+	if err != nil {
+		return err
 	}
 
-	if builtin, ok := object.Builtins[node.Value]; ok {
-		return builtin
+	if _, ok := s.StructType.Methods[fa.Field.Value]; ok {
+		return object.NewEvalErrorObject("%scannot assign to method %s of %s", tokenToPos(fa.Token), fa.Field.Value, s.StructType.Name)
 	}
 
-	return object.NewEvalErrorObject(fmt.Sprintf("identifier not found: %s", node.Value))
-}
+	value := Eval(*right, env)
+	if interrupts(value) {
+		return value
+	}
 
-func evalBangOperatorExpression(right object.Object) object.Object {
-	switch right {
-	case object.TRUE:
-		return object.FALSE
-	case object.FALSE:
-		return object.TRUE
-	case object.NULL:
-		return object.TRUE
-	default:
-		return object.FALSE
+	if !s.StructType.HasField(fa.Field.Value) {
+		return object.NewEvalErrorObject("%s%s has no field %s", tokenToPos(fa.Token), s.StructType.Name, fa.Field.Value)
 	}
-}
 
-func evalMinusPrefixOperatorExpression(right object.Object, tok token.Token) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
-		return object.NewEvalErrorObject("%sunknown operator: -%s", tokenToPos(tok), right.Type())
+	if err := s.CheckField(fa.Field.Value, value); err != nil {
+		return object.NewEvalErrorObject("%sfield %s: %s", tokenToPos(fa.Token), fa.Field.Value, err)
 	}
 
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
-}
+	s.Set(fa.Field.Value, value)
 
-func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
+	if err := checkInvariants(s, fa.Token); err != nil {
+		return err
+	}
+
+	return object.NULL
+}
+
+// isEvaluatedOnAssignment reports whether the value of the right hand side is assigned instead of the expression. The
+// expression is assigned so the variable follows the variables it refers to, unless it refers to variables of an
+// environment nested in scope, like loop variables or parameters, which don't outlive the assignment. Operations
+// without any references are evaluated as well so repeated assignments like i = i + 1 don't build up expressions.
+func isEvaluatedOnAssignment(right ast.Expression, env, scope *object.Environment) bool {
+	references, nested := false, false
+
+	ast.Inspect(right, func(node ast.Node) bool {
+		if identifier, ok := node.(*ast.IdentifierLiteral); ok {
+			if declaring, ok := env.Scope(identifier.Value); ok {
+				references = true
+				nested = nested || declaring != scope && scope.Encloses(declaring)
+			}
+		}
+		return !nested
+	})
+
+	if nested || isComprehension(right) {
+		return true
+	}
+
+	switch right.(type) {
+	case *ast.InfixExpression, *ast.PrefixExpression:
+		return !references
+	default:
+		return false
+	}
+}
+
+// evalAssignIndexExpr assigns to an element of an array or map. An array literal holds expressions so the expression is
+// assigned to it, unless it has to be evaluated like the right hand side of an assignment to a variable. Evaluated
+// arrays and maps hold values so the value is assigned.
+func evalAssignIndexExpr(indexExpr *ast.IndexExpression, index ast.Expression, value *ast.Expression, env *object.Environment) object.Object {
+	target, scope := indexExpr.Left, env
+	if identifier, ok := target.(*ast.IdentifierLiteral); ok { // if index is used on identifier referencing array or map
+		currentValue, ok := env.Get(identifier.Value)
+		if !ok {
+			return object.NewEvalErrorObject(fmt.Sprintf("identifier not found: %q", identifier.Value))
+		}
+		target = *currentValue
+		scope, _ = env.Scope(identifier.Value)
+	}
+
+	key := Eval(index, env)
+	if interrupts(key) {
+		return key
+	}
+
+	switch target := target.(type) {
+	case *ast.ArrayLiteral:
+		i, err := arrayIndex(key, len(target.Elements), indexExpr.Token)
+		if err != nil {
+			return err
+		}
+
+		val := Eval(*value, env)
+		if interrupts(val) {
+			return val
+		}
+
+		if isReference(val) || isEvaluatedOnAssignment(*value, env, scope) {
+			target.Elements[i] = &ast.EvaluatedExpression{Value: val}
+		} else {
+			target.Elements[i] = *value
+		}
+	case *ast.EvaluatedExpression:
+		val := Eval(*value, env)
+		if interrupts(val) {
+			return val
+		}
+
+		switch collection := target.Value.(type) {
+		case *object.Array:
+			i, err := arrayIndex(key, len(collection.Elements), indexExpr.Token)
+			if err != nil {
+				return err
+			}
+			collection.Elements[i] = val
+		case *object.Map:
+			hashable, ok := key.(object.Hashable)
+			if !ok {
+				return object.NewEvalErrorObject("unusable as map key: %s", key.Type())
+			}
+			collection.Set(hashable, val)
+		default:
+			return object.NewEvalErrorObject("index assignment not supported for type %s", collection.(object.Object).Type())
+		}
+	default:
+		return object.NewEvalErrorObject("expected array or map for index expression, got=%T", target)
+	}
+
+	return object.NULL
+}
+
+// arrayIndex checks whether key is an integer indexing an element of an array of the given length
+func arrayIndex(key object.Object, length int, tok token.Token) (int64, object.Object) {
+	return sequenceIndex(key, int64(length), "array", tok)
+}
+
+// sequenceIndex checks whether key is an integer indexing an element of a sequence of the given length, a negative key
+// counts from the end. The kind of sequence is named in the errors positioned at tok.
+func sequenceIndex(key object.Object, length int64, kind string, tok token.Token) (int64, object.Object) {
+	i, ok := key.(*object.Integer)
+	if !ok {
+		return 0, object.NewEvalErrorObject("%sexpected integer for indexing %s, got=%s", tokenToPos(tok), kind, key.Type())
+	}
+
+	index := i.Value
+	if index < 0 {
+		index += length
+	}
+
+	if index < 0 || index >= length {
+		return 0, object.NewEvalErrorObject("%sindex %d out of range for %s of length %d", tokenToPos(tok), i.Value, kind, length)
+	}
+
+	return index, nil
+}
+
+func evalIdentifier(node *ast.IdentifierLiteral, env *object.Environment) object.Object {
+	if expr, ok := env.Get(node.Value); ok {
+		return Eval(*expr, env)
+	}
+
+	if builtin, ok := object.Builtins[node.Value]; ok {
+		return builtin
+	}
+
+	if module, ok := object.LookupModule(node.Value); ok {
+		return module
+	}
+
+	return object.NewEvalErrorObject(fmt.Sprintf("identifier not found: %s", node.Value))
+}
+
+func evalBangOperatorExpression(right object.Object) object.Object {
+	switch right {
+	case object.TRUE:
+		return object.FALSE
+	case object.FALSE:
+		return object.TRUE
+	case object.NULL:
+		return object.TRUE
+	default:
+		return object.FALSE
+	}
+}
+
+func evalMinusPrefixOperatorExpression(right object.Object, tok token.Token) object.Object {
+	if right.Type() != object.INTEGER_OBJ {
+		return object.NewEvalErrorObject("%sunknown operator: -%s", tokenToPos(tok), right.Type())
+	}
+
+	value := right.(*object.Integer).Value
+	return &object.Integer{Value: -value}
+}
+
+func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
@@ -350,16 +1005,135 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	}
 }
 
+// evalStringInfixExpression concatenates strings and compares them by value, lexicographically by code points
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return object.NewEvalErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalIfExpression evaluates to the value of the last statement of the taken branch, when no branch is taken or the
+// taken branch is empty the if expression evaluates to null
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
 	condition := Eval(ie.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	var result object.Object
 
 	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
+		result = Eval(ie.Consequence, env)
 	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
-	} else {
+		result = Eval(ie.Alternative, env)
+	}
+
+	if result == nil {
 		return object.NULL
 	}
+
+	return result
+}
+
+// evalSwitchExpression evaluates the body of the first case with a value equal to the subject, or with a truthy value
+// when there is no subject. The default case is taken when no case matches, regardless of its position. Like an if
+// expression it evaluates to the value of the last statement of the taken body or null.
+func evalSwitchExpression(se *ast.SwitchExpression, env *object.Environment) object.Object {
+	var subject object.Object
+	if se.Subject != nil {
+		subject = Eval(se.Subject, env)
+		if isError(subject) {
+			return subject
+		}
+	}
+
+	var fallback *ast.SwitchCase
+
+	for _, switchCase := range se.Cases {
+		if switchCase.IsDefault() {
+			fallback = switchCase
+			continue
+		}
+
+		for _, value := range switchCase.Values {
+			evaluated := Eval(value, env)
+			if isError(evaluated) {
+				return evaluated
+			}
+
+			if (subject != nil && isEqual(subject, evaluated)) || (subject == nil && isTruthy(evaluated)) {
+				return evalCaseBody(switchCase, env)
+			}
+		}
+	}
+
+	if fallback != nil {
+		return evalCaseBody(fallback, env)
+	}
+
+	return object.NULL
+}
+
+func evalCaseBody(switchCase *ast.SwitchCase, env *object.Environment) object.Object {
+	if result := Eval(switchCase.Body, env); result != nil {
+		return result
+	}
+
+	return object.NULL
+}
+
+// isEqual compares integers and strings by value and arrays by their elements, booleans and null are constants so they
+// compare by identity. Maps, structs and functions are mutable or capture their environment, they are only equal to
+// themselves.
+func isEqual(left, right object.Object) bool {
+	switch left := left.(type) {
+	case *object.Integer:
+		right, ok := right.(*object.Integer)
+		return ok && left.Value == right.Value
+	case *object.String:
+		right, ok := right.(*object.String)
+		return ok && left.Value == right.Value
+	case *object.Array:
+		right, ok := right.(*object.Array)
+		if !ok || len(left.Elements) != len(right.Elements) {
+			return false
+		}
+		for i, element := range left.Elements {
+			if !isEqual(element, right.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return left == right
+	}
+}
+
+func evalTernaryExpression(te *ast.TernaryExpression, env *object.Environment) object.Object {
+	condition := Eval(te.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Eval(te.Consequence, env)
+	}
+
+	return Eval(te.Alternative, env)
 }
 
 func evalStringLiteral(s *ast.StringLiteral, env *object.Environment) object.Object {
@@ -373,6 +1147,9 @@ func evalStringLiteral(s *ast.StringLiteral, env *object.Environment) object.Obj
 
 		if exprPart != nil {
 			val := Eval(exprPart.Expression, env)
+			if interrupts(val) {
+				return val
+			}
 			out.WriteString(toString(val))
 		}
 
@@ -392,62 +1169,451 @@ func evalStringLiteral(s *ast.StringLiteral, env *object.Environment) object.Obj
 
 func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
 	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
 	idx := Eval(node.Index, env)
+	if isError(idx) {
+		return idx
+	}
 
 	if array, ok := left.(*object.Array); ok {
-		if intIdx, ok := idx.(*object.Integer); ok {
-			max := int64(len(array.Elements) - 1)
-			if intIdx.Value < 0 || intIdx.Value > max {
-				return object.NULL
+		i, err := arrayIndex(idx, len(array.Elements), node.Token)
+		if err != nil {
+			return err
+		}
+		return array.Elements[i]
+	}
+
+	if s, ok := left.(*object.String); ok {
+		runes := []rune(s.Value)
+		i, err := sequenceIndex(idx, int64(len(runes)), "string", node.Token)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: string(runes[i])}
+	}
+
+	if r, ok := left.(*object.Range); ok {
+		length, ok := r.Len()
+		if i, isInteger := idx.(*object.Integer); isInteger && !ok {
+			// the range is longer than any index, only counting from its end needs its length
+			if i.Value < 0 {
+				return object.NewEvalErrorObject("%slength of range %s exceeds the largest integer", tokenToPos(node.Token), r.Inspect())
 			}
-			return array.Elements[intIdx.Value]
+			return r.At(i.Value)
 		}
+
+		i, err := sequenceIndex(idx, length, "range", node.Token)
+		if err != nil {
+			return err
+		}
+		return r.At(i)
+	}
+
+	if m, ok := left.(*object.Map); ok {
+		key, ok := idx.(object.Hashable)
+		if !ok {
+			return object.NewEvalErrorObject("unusable as map key: %s", idx.Type())
+		}
+
+		if value, ok := m.Get(key); ok {
+			return value
+		}
+		return object.NULL
 	}
 
-	return object.NewEvalErrorObject("indexing for type %T not implemented", node.Left)
+	return object.NewEvalErrorObject("%scannot index %s", tokenToPos(node.Token), left.Type())
 }
 
-func evalSliceExpression(node *ast.SliceLiteral, env *object.Environment) object.Object {
-	var (
-		lower *object.Integer
-		upper *object.Integer
-	)
+// evalRangeExpression evaluates the bounds and step of a range, without a step it counts up by one or down by one when
+// the upper bound is below the lower one
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	var bounds [3]int64
+	for i, part := range []struct {
+		name string
+		expr ast.Expression
+	}{{"lower bound", node.Lower}, {"upper bound", node.Upper}, {"step", node.Step}} {
+		if part.expr == nil {
+			continue
+		}
 
-	evaluated := Eval(node.Left, env)
-	array, ok := evaluated.(*object.Array)
+		val := Eval(part.expr, env)
+		if isError(val) {
+			return val
+		}
+
+		integer, ok := val.(*object.Integer)
+		if !ok {
+			return object.NewEvalErrorObject("%s%s of range must be of type integer got=%s", tokenToPos(node.Token), part.name, val.Type())
+		}
+		bounds[i] = integer.Value
+	}
+
+	r := &object.Range{Start: bounds[0], End: bounds[1], Step: bounds[2], Exclusive: node.Exclusive}
+	switch {
+	case node.Step == nil && r.End < r.Start:
+		r.Step = -1
+	case node.Step == nil:
+		r.Step = 1
+	case r.Step == 0:
+		return object.NewEvalErrorObject("%srange step cannot be zero", tokenToPos(node.Token))
+	}
+
+	return r
+}
+
+// evalMapLiteral evaluates the pairs in source order, a repeated key keeps its first position and its last value
+func evalMapLiteral(node *ast.MapLiteral, env *object.Environment) object.Object {
+	m := object.NewMap()
+
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return object.NewEvalErrorObject("%sunusable as map key: %s", tokenToPos(node.Token), key.Type())
+		}
+
+		value := Eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		m.Set(hashable, value)
+	}
+
+	return m
+}
+
+func evalTypeStatement(ts *ast.TypeStatement, env *object.Environment) object.Object {
+	switch declaration := ts.Type.(type) {
+	case *ast.StructType:
+		typeParameters, err := resolveTypeParameters(ts.TypeParameters, env)
+		if err != nil {
+			return err
+		}
+
+		structType := newStructType(ts.Name, declaration, env)
+		structType.TypeParameters = typeParameters
+		bindValue(env, ts.Name, structType)
+	case *ast.InterfaceType:
+		if len(ts.TypeParameters) > 0 {
+			return object.NewEvalErrorObject("%sinterface %s can't have type parameters", tokenToPos(ts.Token), ts.Name.Value)
+		}
+
+		bindValue(env, ts.Name, &object.InterfaceType{Name: ts.Name.Value, Methods: declaration.Methods})
+	default:
+		return object.NewEvalErrorObject("%stype declaration %T not supported", tokenToPos(ts.Token), ts.Type)
+	}
+
+	return object.NULL
+}
+
+func newStructType(name *ast.IdentifierLiteral, declaration *ast.StructType, env *object.Environment) *object.StructType {
+	structType := &object.StructType{
+		Name:       name.Value,
+		FieldTypes: make(map[string]object.FieldType),
+		Methods:    make(map[string]*object.Function),
+		Invariants: declaration.Invariants,
+		Env:        env,
+	}
+
+	for _, field := range declaration.Fields {
+		structType.Fields = append(structType.Fields, field.Name.Value)
+		if field.Type != nil {
+			structType.FieldTypes[field.Name.Value] = object.FieldType{Name: field.Type.Value, Nullable: field.Nullable}
+		}
+	}
+
+	for _, method := range declaration.Methods {
+		structType.Methods[method.Name.Value] = &object.Function{
+			Parameters:     method.Function.Parameters,
+			ReturnType:     method.Function.ReturnType,
+			ReturnNullable: method.Function.ReturnNullable,
+			Requires:       method.Function.Requires,
+			Ensures:        method.Function.Ensures,
+			Body:           method.Function.Body,
+			Env:            env,
+		}
+	}
+
+	return structType
+}
+
+// evalStructLiteral creates a struct, the fields missing in the literal are null so they have to be nullable
+func evalStructLiteral(sl *ast.StructLiteral, env *object.Environment) object.Object {
+	typeValue := Eval(sl.Type, env)
+	if isError(typeValue) {
+		return typeValue
+	}
+
+	structType, ok := typeValue.(*object.StructType)
 	if !ok {
-		return object.NewEvalErrorObject("indexing for type %T not implemented", node.Left)
+		return object.NewEvalErrorObject("%s%s is not a struct type, got=%s", tokenToPos(sl.Token), sl.Type.Value, typeValue.Type())
 	}
 
-	if node.Lower != nil {
-		val := Eval(*node.Lower, env)
-		if intObj, ok := val.(*object.Integer); ok {
-			lower = intObj
-		} else {
-			return object.NewEvalErrorObject("lower bound of slice must be of type integer got=%s", val.Type())
+	s := object.NewStruct(structType)
+
+	for _, field := range sl.Fields {
+		value := Eval(field.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		if !structType.HasField(field.Name.Value) {
+			return object.NewEvalErrorObject("%s%s has no field %s", tokenToPos(field.Name.Token), structType.Name, field.Name.Value)
 		}
+
+		if err := s.CheckField(field.Name.Value, value); err != nil {
+			return object.NewEvalErrorObject("%sfield %s: %s", tokenToPos(field.Name.Token), field.Name.Value, err)
+		}
+
+		s.Set(field.Name.Value, value)
 	}
-	if node.Upper != nil {
-		val := Eval(*node.Upper, env)
-		if intObj, ok := val.(*object.Integer); ok {
-			upper = intObj
-		} else {
-			return object.NewEvalErrorObject("lower bound of slice must be of type integer got=%s", val.Type())
+
+	for _, name := range structType.Fields {
+		if !sl.HasField(name) {
+			if err := s.CheckField(name, object.NULL); err != nil {
+				return object.NewEvalErrorObject("%sfield %s: %s", tokenToPos(sl.Token), name, err)
+			}
 		}
 	}
 
-	if len(array.Elements) == 0 {
-		return &object.Array{}
-	} else if lower != nil && upper != nil {
-		return &object.Array{Elements: array.Elements[lower.Value:upper.Value]}
-	} else if lower != nil {
-		return &object.Array{Elements: array.Elements[lower.Value:]}
-	} else if upper != nil {
-		return &object.Array{Elements: array.Elements[:upper.Value]}
+	if err := checkInvariants(s, sl.Token); err != nil {
+		return err
 	}
 
-	return &object.Array{Elements: array.Elements}
+	return s
+}
 
+// evalFieldAccessExpression evaluates to the value of a field, or to the method bound to the struct
+func evalFieldAccessExpression(fa *ast.FieldAccessExpression, env *object.Environment) object.Object {
+	left := Eval(fa.Left, env)
+	if module, ok := left.(*object.Module); ok {
+		if member, ok := module.Members[fa.Field.Value]; ok {
+			return member
+		}
+		return object.NewEvalErrorObject("%smodule %s has no member %s", tokenToPos(fa.Token), module.Name, fa.Field.Value)
+	}
+
+	s, err := structOperand(fa, left)
+	if err != nil {
+		return err
+	}
+
+	if value, ok := s.Get(fa.Field.Value); ok {
+		return value
+	}
+
+	if method, ok := s.StructType.Methods[fa.Field.Value]; ok {
+		return bindMethod(method, s)
+	}
+
+	return object.NewEvalErrorObject("%s%s has no field or method %s", tokenToPos(fa.Token), s.StructType.Name, fa.Field.Value)
+}
+
+// evalStructOperand evaluates the struct of a field access. A safe access of null returns null in place of the error,
+// so callers return it as the result of the access.
+func evalStructOperand(fa *ast.FieldAccessExpression, env *object.Environment) (*object.Struct, object.Object) {
+	return structOperand(fa, Eval(fa.Left, env))
+}
+
+// structOperand checks the evaluated left side of a field access is a struct
+func structOperand(fa *ast.FieldAccessExpression, left object.Object) (*object.Struct, object.Object) {
+	if isError(left) {
+		return nil, left
+	}
+
+	if left == object.NULL && fa.IsSafe() {
+		return nil, object.NULL
+	}
+
+	s, ok := left.(*object.Struct)
+	if !ok {
+		return nil, object.NewEvalErrorObject("%s%s has no field or method %s", tokenToPos(fa.Token), left.Type(), fa.Field.Value)
+	}
+
+	return s, nil
+}
+
+// bindMethod returns the method as function with self bound to the receiving struct, a bound method can be passed
+// around like any other function
+func bindMethod(method *object.Function, receiver *object.Struct) *object.Function {
+	return &object.Function{
+		Parameters:     method.Parameters,
+		ReturnType:     method.ReturnType,
+		ReturnNullable: method.ReturnNullable,
+		Requires:       method.Requires,
+		Ensures:        method.Ensures,
+		Body:           method.Body,
+		Env:            selfEnvironment(method.Env, receiver),
+	}
+}
+
+// selfEnvironment encloses the environment binding self to the receiving struct
+func selfEnvironment(outer *object.Environment, receiver *object.Struct) *object.Environment {
+	env := object.NewEnclosedEnvironment(outer)
+
+	var self ast.Expression = &ast.EvaluatedExpression{Value: receiver}
+	env.Set("self", &self)
+
+	return env
+}
+
+func evalSliceExpression(node *ast.SliceLiteral, env *object.Environment) object.Object {
+	evaluated := Eval(node.Left, env)
+	if isError(evaluated) {
+		return evaluated
+	}
+
+	var length int64
+	switch evaluated := evaluated.(type) {
+	case *object.Array:
+		length = int64(len(evaluated.Elements))
+	case *object.String:
+		length = int64(utf8.RuneCountInString(evaluated.Value))
+	case *object.Range:
+		var ok bool
+		if length, ok = evaluated.Len(); !ok {
+			return object.NewEvalErrorObject("%slength of range %s exceeds the largest integer", tokenToPos(node.Token), evaluated.Inspect())
+		}
+	default:
+		return object.NewEvalErrorObject("%scannot slice %s", tokenToPos(node.Token), evaluated.Type())
+	}
+
+	lower, err := evalSliceBound(node.Lower, "lower bound", env)
+	if err != nil {
+		return err
+	}
+	upper, err := evalSliceBound(node.Upper, "upper bound", env)
+	if err != nil {
+		return err
+	}
+	step, err := evalSliceBound(node.Step, "step", env)
+	if err != nil {
+		return err
+	}
+
+	stepValue := int64(1)
+	if step != nil {
+		stepValue = step.Value
+	}
+	if stepValue == 0 {
+		return object.NewEvalErrorObject("%sslice step cannot be zero", tokenToPos(node.Token))
+	}
+
+	lo, hi, ok := sliceBounds(lower, upper, stepValue, length)
+	if !ok {
+		return object.NewEvalErrorObject("%sslice bounds out of range %s with length %d", tokenToPos(node.Token), writtenBounds(lower, upper, step), length)
+	}
+
+	switch evaluated := evaluated.(type) {
+	case *object.String:
+		return &object.String{Value: string(sliceOf([]rune(evaluated.Value), lo, hi, stepValue))}
+	case *object.Range:
+		return sliceRange(evaluated, lo, hi, stepValue, node.Token)
+	default:
+		return &object.Array{Elements: sliceOf(evaluated.(*object.Array).Elements, lo, hi, stepValue)}
+	}
+}
+
+// writtenBounds formats the bounds and step of a slice as they are written, omitted ones are left empty
+func writtenBounds(lower, upper, step *object.Integer) string {
+	format := func(bound *object.Integer) string {
+		if bound == nil {
+			return ""
+		}
+		return strconv.FormatInt(bound.Value, 10)
+	}
+
+	if step == nil {
+		return fmt.Sprintf("[%s:%s]", format(lower), format(upper))
+	}
+	return fmt.Sprintf("[%s:%s:%s]", format(lower), format(upper), format(step))
+}
+
+// sliceRange returns the integers of the range from index lo up to hi in steps of step as a range, it isn't copied into
+// an array as the range may be larger than any array
+func sliceRange(r *object.Range, lo, hi, step int64, tok token.Token) object.Object {
+	var count uint64 // the number of indices from lo up to hi, hi is left out
+	switch {
+	case step > 0 && lo < hi:
+		count = uint64(hi-lo-1)/uint64(step) + 1
+	case step < 0 && lo > hi:
+		count = uint64(lo-hi-1)/(uint64(-(step+1))+1) + 1
+	default:
+		return &object.Range{Start: r.Start, End: r.Start, Step: 1, Exclusive: true}
+	}
+
+	first, last := r.At(lo).Value, r.At(lo+int64(count-1)*step).Value
+	if count == 1 {
+		return &object.Range{Start: first, End: last, Step: 1}
+	}
+
+	product := r.Step * step
+	if product/step != r.Step || (r.Step == -1 && step == math.MinInt64) || (step == -1 && r.Step == math.MinInt64) {
+		return object.NewEvalErrorObject("%sstep of the sliced range exceeds the largest integer", tokenToPos(tok))
+	}
+
+	return &object.Range{Start: first, End: last, Step: product}
+}
+
+// sliceOf copies the items from lo up to hi in steps of step, backwards when the step is negative
+func sliceOf[T any](items []T, lo, hi, step int64) []T {
+	var sliced []T
+	for i := lo; step > 0 && i < hi || step < 0 && i > hi; i += step {
+		sliced = append(sliced, items[i])
+	}
+	return sliced
+}
+
+// evalSliceBound evaluates a bound or the step of a slice, it is nil when omitted
+func evalSliceBound(bound *ast.Expression, name string, env *object.Environment) (*object.Integer, object.Object) {
+	if bound == nil {
+		return nil, nil
+	}
+
+	val := Eval(*bound, env)
+	if isError(val) {
+		return nil, val
+	}
+
+	i, ok := val.(*object.Integer)
+	if !ok {
+		return nil, object.NewEvalErrorObject("%s of slice must be of type integer got=%s", name, val.Type())
+	}
+
+	return i, nil
+}
+
+// sliceBounds resolves the bounds of a slice of an array of the given length, a negative bound counts from the end.
+// Omitted bounds span the whole array in the direction of the step, ok reports whether the bounds lie within the array
+// in that direction.
+func sliceBounds(lower, upper *object.Integer, step, length int64) (lo, hi int64, ok bool) {
+	resolve := func(bound *object.Integer, omitted int64) int64 {
+		switch {
+		case bound == nil:
+			return omitted
+		case bound.Value < 0:
+			return bound.Value + length
+		default:
+			return bound.Value
+		}
+	}
+
+	if step > 0 {
+		lo, hi = resolve(lower, 0), resolve(upper, length)
+		return lo, hi, 0 <= lo && lo <= hi && hi <= length
+	}
+
+	// slicing backwards stops before the upper bound, -1 is before the first element
+	lo, hi = resolve(lower, length-1), resolve(upper, -1)
+	return lo, hi, -1 <= hi && hi <= lo && lo < length
 }
 
 // copyArray makes a shallow copy of the array
@@ -459,7 +1625,8 @@ func copyArray[T any](source []T) []T {
 
 // shallowCopySliceLiteral makes a shallow slice copy, when slicing of identifier which points to array it makes a shallow copy to slice on
 func shallowCopySliceLiteral(slice *ast.SliceLiteral, env *object.Environment) *ast.SliceLiteral {
-	if _, ok := slice.Left.(*ast.ArrayLiteral); ok {
+	switch slice.Left.(type) {
+	case *ast.ArrayLiteral, *ast.StringLiteral:
 		return slice
 	}
 	id, ok := slice.Left.(*ast.IdentifierLiteral)
@@ -470,9 +1637,12 @@ func shallowCopySliceLiteral(slice *ast.SliceLiteral, env *object.Environment) *
 	if !ok {
 		panic(fmt.Sprintf("identifier %q not found, unable to slice on", id.Value))
 	}
+	if _, ok := (*identifierValue).(*ast.EvaluatedExpression); ok { // slicing the value creates a new array
+		return slice
+	}
 	array, ok := (*identifierValue).(*ast.ArrayLiteral)
-	if !ok {
-		panic(fmt.Sprintf("slice not supported for type %T", identifierValue))
+	if !ok { // other values like strings are immutable, slicing them creates a new value as well
+		return slice
 	}
 
 	newArray := ast.ArrayLiteral{
@@ -485,9 +1655,12 @@ func shallowCopySliceLiteral(slice *ast.SliceLiteral, env *object.Environment) *
 		Left:  &newArray,
 		Lower: slice.Lower,
 		Upper: slice.Upper,
+		Step:  slice.Step,
 	}
 }
 
+// toString returns the text a value is interpolated into a string as, strings are inserted without quotes and any
+// other value as it is printed
 func toString(obj object.Object) string {
 	switch obj := obj.(type) {
 	case *object.String:
@@ -499,10 +1672,11 @@ func toString(obj object.Object) string {
 			return "true"
 		}
 		return "false"
-	case *object.Null: // todo identifier type
-		return "NULL"
+	case nil:
+		return object.NULL.Inspect()
+	default:
+		return obj.Inspect()
 	}
-	panic(fmt.Sprintf("can't stringify type %T", obj))
 }
 
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
@@ -526,9 +1700,20 @@ func isTruthy(obj object.Object) bool {
 	}
 }
 
+// interrupts reports whether evaluating an expression was interrupted by an error, return, break or continue, which is
+// passed up to the enclosing function or loop instead of being used as value
+func interrupts(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.EvalError, *object.ContractError, *object.ReturnValue, *object.Break, *object.Continue:
+		return true
+	default:
+		return false
+	}
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ
+		return obj.Type() == object.ERROR_OBJ || obj.Type() == object.CONTRACT_ERROR_OBJ
 	}
 	return false
 }
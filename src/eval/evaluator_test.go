@@ -7,6 +7,7 @@ import (
 	"Flow/src/ast"
 	"Flow/src/object"
 	"Flow/src/parser"
+	"Flow/src/stdlib"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -94,6 +95,12 @@ func (test *Suite) TestIfElseExpressions() {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } else if (1 > 0) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } else if (1 < 0) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } elif (1 < 0) { 20 } elif (2 > 1) { 30 }", 30},
+		{"if (1 > 2) { 10 } elif (1 < 0) { 20 }", nil},
+		{"if (1 < 2) { } else { 20 }", nil},
+		{"if (1 < 2) { let a = 1; }", nil},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +115,228 @@ func (test *Suite) TestIfElseExpressions() {
 	}
 }
 
+func (test *Suite) TestIfExpressionValues() {
+	tests := []struct {
+		input    string
+		expected interface{}
+		stmts    int
+	}{
+		{"let x = if (1 < 2) { 1 } else { 2 }; x;", 1, 2},
+		{"let n = 5; let size = if n > 9 { 3 } elif n > 4 { 2 } else { 1 }; size;", 2, 3},
+		{"let x = if (1 > 2) { 1 }; x;", nil, 2},
+		{"let x = 10 + if (true) { 5 } else { 0 }; x;", 15, 2},
+		{"let f = (n) => { if n > 0 { return 1; } elif n < 0 { return -1; }; 0 }; f(-4);", -1, 2},
+		{"if (1 + true) { 1 }", "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, tt.stmts, env)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(test.T(), *unwrapObservable(evaluated, env), int64(expected))
+		case string:
+			test.IsType(&object.EvalError{}, evaluated)
+			test.Contains(evaluated.Inspect(), expected)
+		default:
+			testNullObject(test.T(), *unwrapObservable(evaluated, env))
+		}
+	}
+}
+
+func (test *Suite) TestSwitchExpressions() {
+	tests := []struct {
+		input    string
+		expected interface{}
+		stmts    int
+	}{
+		{"let x = 2; switch x { case 1, 2: 10 case 3: 30 default: 0 }", 10, 2},
+		{"let x = 3; switch x { case 1, 2: 10 case 3: 30 default: 0 }", 30, 2},
+		{"let x = 9; switch x { default: 0 case 1, 2: 10 }", 0, 2},
+		{"let x = 9; switch x { case 1: 10 }", nil, 2},
+		{"let x = 1; switch x { case 1: }", nil, 2},
+		{`let s = "b"; switch s { case "a": 1 case "b": 2 }`, 2, 2},
+		{"let n = -5; let sign = switch { case n > 0: 1 case n < 0: -1 default: 0 }; sign;", -1, 3},
+		{"let n = 0; switch { case n > 0: 1 case n < 0: -1 default: 0 }", 0, 2},
+		{"switch true { case 1 > 2: 1 case 2 > 1: 2 }", 2, 1},
+		{"let f = (x) => { switch x { case 1: return 10; }; 20 }; f(1);", 10, 2},
+		{"switch 1 + true { case 1: 1 }", "type mismatch: INTEGER + BOOLEAN", 1},
+		{"switch 1 { case 1 + true: 1 }", "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, tt.stmts, env)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(test.T(), evaluated, int64(expected))
+		case string:
+			test.IsType(&object.EvalError{}, evaluated)
+			test.Contains(evaluated.Inspect(), expected)
+		default:
+			testNullObject(test.T(), evaluated)
+		}
+	}
+}
+
+func (test *Suite) TestForStatements() {
+	tests := []struct {
+		input    string
+		expected interface{}
+		stmts    int
+	}{
+		{"let i = 0; for i < 5 { i = i + 1 }; i;", 5, 3},
+		{"let sum = 0; for let i = 1; i < 5; i = i + 1 { sum = sum + i }; sum;", 10, 3},
+		{"let sum = 0; for x in [1, 2, 3, 4] { if x == 3 { continue }; sum = sum + x }; sum;", 7, 3},
+		{"let sum = 0; for x, i in [10, 20, 30] { sum = sum + x * i }; sum;", 80, 3},
+		{"let count = 0; for _, i in [7, 8, 9] { count = i }; count;", 2, 3},
+		{"let i = 0; for { i = i + 1; if i > 3 { break } }; i;", 4, 3},
+		{"let sum = 0; for x in [1, 2] { for y in [10, 20] { if y == 20 { break }; sum = sum + x * y } }; sum;", 30, 3},
+		{"let sum = 0; for x in [1, 2, 3] { let double = x * 2; sum = sum + double }; sum;", 12, 3},
+		{"let find = (xs) => { for x in xs { if x > 2 { return x } }; return -1 }; find([1, 3, 5]);", 3, 2},
+		{"let i = 0; let found = switch { default: for x in [4, 5] { i = x }; i }; found;", 5, 3},
+		{"let n = 0; for x in [1, 2] { let y = if true { break }; n = n + 1 }; n;", 0, 3},
+		{"let n = 0; for x in [1, 2, 3] { n = n + if x == 2 { continue } else { x } }; n;", 4, 3},
+		{"let n = 0; for x in [1, 2, 3] { n = n + if x == 2 { break } else { x } }; n;", 1, 3},
+		{"let n = 0; for x in [1, 2] { len(if true { break } else { [] }); n = n + 1 }; n;", 0, 3},
+		{"let n = 0; for x in [1, 2, 3] { n = switch x { case 2: continue default: n + x } }; n;", 4, 3},
+		{"let n = 0; for x in [1, 2, 3] { let a, b = if x == 2 { continue } else { [x, x] }; n = n + a + b }; n;", 8, 3},
+		{"let f = () => { for x in [1, 2] { let y = -(if x == 2 { return 7 } else { x }) }; return 0 }; f();", 7, 2},
+		{"for x in [] { x }", nil, 1},
+		{"for i < 3 { }", "could not find identifier i", 1},
+		{"for x in 5 { x }", "1:1: cannot iterate over INTEGER", 1},
+		{"for x in [1] { x + true }", "type mismatch: INTEGER + BOOLEAN", 1},
+		{"for let i = 0; i < 3; i = i + true { }", "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, tt.stmts, env)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(test.T(), evaluated, int64(expected))
+		case string:
+			if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+				test.Contains(evaluated.Inspect(), expected)
+			}
+		default:
+			testNullObject(test.T(), evaluated)
+		}
+	}
+}
+
+func (test *Suite) TestMapLiterals() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"{}", "{}", 1},
+		{`let k = "b"; {"a": 1, k: 1 + 1, 3: true, false: [1]}`, "{a: 1, b: 2, 3: true, false: [1]}", 2},
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}", 1},
+		{`let m = {"a": 1}; m["b"] = 2; m["a"] = 3; m;`, "{a: 3, b: 2}", 4},
+		{`let m = {"a": 1}; let n = m; n["b"] = 2; m;`, "{a: 1, b: 2}", 4},
+		{`let m = {}; for x, i in [5, 6] { m[x] = i }; m;`, "{5: 0, 6: 1}", 3},
+		{`let m = {"a": 1, "b": 2, "c": 3}; delete(m, "b"); delete(m, "x"); m["b"] = 4; m;`, "{a: 1, c: 3, b: 4}", 5},
+		{`let make = () => { return {"a": 1} }; let m = make(); m["b"] = 2; m;`, "{a: 1, b: 2}", 4},
+		{`let m = {"a": 1}; m = {"b": 2}; m["c"] = 3; m;`, "{b: 2, c: 3}", 4},
+		{`[keys({"b": 1, "a": 2}), values({"b": 1, "a": 2}), len({1: 1})]`, "[[b, a], [1, 2], 1]", 1},
+		{`let m = {"a": 1}; [has(m, "a"), has(m, "b"), m["a"], m["b"]]`, "[true, false, 1, null]", 2},
+		{`let sum = 0; for v, k in {"a": 1, "b": 2} { sum = sum + v }; sum;`, "3", 3},
+		{`let m = {"a": 1, "b": 2}; for _, k in m { delete(m, k) }; m;`, "{}", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestMapErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"{[1]: 2}", "1:1: unusable as map key: ARRAY", 1},
+		{`{"a": 1}[[1]]`, "unusable as map key: ARRAY", 1},
+		{`let m = {}; m[[1]] = 1;`, "unusable as map key: ARRAY", 2},
+		{`{"a": 1 + true}`, "type mismatch: INTEGER + BOOLEAN", 1},
+		{`keys([1])`, `argument to "keys" must be MAP, got=ARRAY`, 1},
+		{`has()`, "expected 2 argument(s) for has got=0", 1},
+		{`delete({}, [1])`, "unusable as map key: ARRAY", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestForStatementClosures() {
+	tests := []struct {
+		input    string
+		expected int64
+		stmts    int
+	}{
+		{"let adders = [0, 0, 0]; for let j = 0; j < 3; j = j + 1 { adders[j] = (x) => x + j }; adders[0](100);", 100, 3},
+		{"let adders = [0, 0, 0]; for let j = 0; j < 3; j = j + 1 { adders[j] = (x) => x + j }; adders[2](100);", 102, 3},
+		{"let fs = [0, 0]; for let j = 0; j < 4; j = j + 1 { fs[j / 2] = () => j; j = j + 1 }; fs[0]() * 10 + fs[1]();", 13, 3},
+		{"let fs = [0, 0]; for let a, b = [0, 10]; a < 2; a = a + 1 { fs[a] = () => a + b; b = b + 1 }; fs[1]();", 13, 3},
+		{"let i = 0; let fs = [0, 0]; for i = 0; i < 2; i = i + 1 { fs[i] = () => i }; fs[0]();", 2, 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		testIntegerObject(test.T(), evaluated, tt.expected)
+	}
+}
+
+func (test *Suite) TestForStatementsLargeIterationCount() {
+	input := "let sum = 0; for let i = 0; i < 100000; i = i + 1 { sum = sum + i }; sum;"
+	evaluated := testEval(test.T(), input, 3, object.NewEnvironment())
+	testIntegerObject(test.T(), evaluated, 4999950000)
+}
+
+func (test *Suite) TestAssignmentScope() {
+	tests := []struct {
+		input    string
+		expected int64
+		stmts    int
+	}{
+		{"let count = 0; let inc = () => { count = count + 1 }; inc(); inc(); count;", 2, 5},
+		{"let total = 0; let add = (x) => { total = total + x }; add(2); add(3); total;", 5, 5},
+		{"let a = 1; let b = 0; b = a + 1; a = 5; b;", 6, 5},
+		{"let xs = [1, 2]; for x in [3] { xs = [x, x] }; xs[0] = 7; xs[0] + xs[1];", 10, 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		testIntegerObject(test.T(), evaluated, tt.expected)
+	}
+}
+
+func (test *Suite) TestTernaryExpressions() {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"true ? 1 : 2", 1},
+		{"false ? 1 : 2", 2},
+		{"1 > 2 ? 1 : 2 + 3", 5},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, 1, env)
+		testIntegerObject(test.T(), evaluated, tt.expected)
+	}
+}
+
 func (test *Suite) TestLetStatements() {
 	tests := []struct {
 		input    string
@@ -120,6 +349,7 @@ func (test *Suite) TestLetStatements() {
 		{"let a = 5; let b = a; let c = a + b + 5; c;", 15, 4},
 		{"let a = 5; a = 10; a", 10, 3},
 		{"let a = 10; let b = 7; a = a + b; a;", 17, 4},
+		{"let snake_case = 5; _ = snake_case + 1; snake_case;", 5, 3},
 	}
 
 	for _, tt := range tests {
@@ -169,6 +399,17 @@ func (test *Suite) TestFunctionApplication() {
 		{"let add = (x, y) => { x + y; }; add(5 + 5, add(5, 5));", 20, 2},
 		{"(x) => { x; }(5);", 5, 1},
 		{"let identity = (x) => { return x; }; identity(\"test\");", "test", 2},
+		{"let inc = (x) => x + 1; inc(1);", 2, 2},
+		{"((x) => x * 2)(4);", 8, 1},
+		{"let max = (a, b) => a > b ? a : b; max(3, 7);", 7, 2},
+		{"let apply = (fn, x) => fn(x); apply((x) => x + 2, 3);", 5, 2},
+		{"let add = (x) => (y) => x + y; add(2)(3);", 5, 2},
+		{"let sign = x => x < 0 ? -1 : 1; sign(-4);", -1, 2},
+		{"let apply = (fn, x) => fn(x); apply(x => x * 3, 3);", 9, 2},
+		{"let id = (a string) string => a; id(\"s\");", "s", 2},
+		{"let second = (_, i) => i; second(1, 2);", 2, 2},
+		{"let both = (_, _) => 3; both(1, 2);", 3, 2},
+		{"let snake_case = 4; let _ = 5; snake_case;", 4, 3},
 	}
 
 	for _, tt := range tests {
@@ -267,6 +508,48 @@ func (test *Suite) TestStringLiteral() {
 	testStringObject(test.T(), result, "foo 64 bar")
 }
 
+func (test *Suite) TestStringLiteralEscapesAndInterpolation() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`"say \"hi\"\n"`, "say \"hi\"\n", 1},
+		{`"tab\there \\ \u{1F30A}"`, "tab\there \\ 🌊", 1},
+		{`let x = 7; "Value <x>";`, "Value 7", 2},
+		{`let x = 7; "<x + 1> ${x - 1} <(x > 3)>";`, "8 6 true", 2},
+		{`"\<x> \${x}"`, "<x> ${x}", 1},
+		{`let m = {"a": 1}; "m=<m>"`, "m={a: 1}", 2},
+		{`type P struct { x; y }; let p = P{x: 1, y: "b"}; "p=${p}"`, "p=P{x: 1, y: b}", 3},
+		{`"r=${1..3} s=${(0..9 step 3)}"`, "r=1..3 s=0..9 step 3", 1},
+		{`let xs = [1, [2]]; "xs=<xs>"`, "xs=[1, [2]]", 2},
+		{`let f = () => {}; "f=${f()}"`, "f=null", 2},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, tt.stmts, env)
+		testStringObject(test.T(), evaluated, tt.expected)
+	}
+}
+
+func (test *Suite) TestRawStringLiterals() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`"""say "hi" \n"""`, `say "hi" \n`, 1},
+		{"let table = \"users\";\nlet id = 4;\n\"\"\"\n    SELECT *\n      FROM ${table}\n     WHERE id = ${id + 1}\n    \"\"\"", "SELECT *\n  FROM users\n WHERE id = 5", 3},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		evaluated := testEval(test.T(), tt.input, tt.stmts, env)
+		testStringObject(test.T(), evaluated, tt.expected)
+	}
+}
+
 func (test *Suite) TestNativeFunctions() {
 	tests := []struct {
 		input    string
@@ -333,8 +616,6 @@ func (test *Suite) TestArrayIndexing() {
 		{"let myArray = [1, 2, 3]; myArray[2];", 3, 2},
 		{"let myArray = [1, 2, 3]; let i = myArray[0] + myArray[1] + myArray[2]; i;", 6, 3},
 		{"let myArray = [1, 2, 3]; let i = myArray[0]; myArray[i];", 2, 3},
-		{"[1, 2, 3][3]", nil, 1},  // todo might want to return error here...?
-		{"[1, 2, 3][-1]", nil, 1}, // todo might want to return 3 here...?
 		{"[1, 2, 3][:];", []int{1, 2, 3}, 1},
 		{"[1, 2, 3][1:];", []int{2, 3}, 1},
 		{"[1, 2, 3][:2];", []int{1, 2}, 1},
@@ -345,6 +626,9 @@ func (test *Suite) TestArrayIndexing() {
 		{"let arr = [1,2,3]; arr[1] = 7;", nil, 2},
 		{"let arr = [1, 2, 3]; arr[2] = 7; arr[2];", 7, 3},
 		{"let arr = [1, 2, 3]; let b = arr[:]; arr[0] = 7; b[0];", 1, 4},
+		{"[1, 2, 3][-1]", 3, 1},
+		{"[1, 2, 3][-3]", 1, 1},
+		{"let arr = [1, 2, 3]; arr[-1] = 7; arr[2];", 7, 3},
 	}
 
 	for _, tt := range tests {
@@ -405,3 +689,1200 @@ func (test *Suite) TestStringLiteralEvaluation() {
 	}
 	assert.Equal(test.T(), "n:0   n>0:false", stringResult.Value)
 }
+
+func (test *Suite) TestStructs() {
+	const point = "type Point struct { x; y; sum() => self.x + self.y; move(dx) => { self.x = self.x + dx } };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{point + "Point{x: 1}", "Point{x: 1, y: null}", 2},
+		{point + "Point", "struct Point", 2},
+		{point + "let p = Point{x: 1, y: 2}; p.x = 3; [p.x, p.y]", "[3, 2]", 4},
+		{point + "let p = Point{x: 1, y: 2}; p.move(5); p.sum()", "8", 4},
+		{point + "let p = Point{x: 1, y: 2}; let q = p; q.x = 10; p", "Point{x: 10, y: 2}", 5},
+		{point + "let p = Point{x: 1, y: 2}; let sum = p.sum; p.x = 2; sum()", "4", 5},
+		{point + "let apply = (fn) => fn(); apply(Point{x: 1, y: 1}.sum)", "2", 3},
+		{point + "let p = Point{x: 1}; let t = p.x + 1; p.x = 5; t", "6", 5},
+		{point + "let ps = [Point{x: 1}, Point{x: 2}]; for p in ps { p.move(1) }; ps", "[Point{x: 2, y: null}, Point{x: 3, y: null}]", 4},
+		{point + "let make = () => { return Point{x: 1, y: 1} }; make().sum()", "2", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+type fieldChangeObserver struct {
+	changes []string
+}
+
+func (o *fieldChangeObserver) Notify(change *object.FieldChange) {
+	o.changes = append(o.changes, change.Name+"="+change.Value.Inspect())
+}
+
+func (test *Suite) TestStructObservers() {
+	env := object.NewEnvironment()
+	evaluated := testEval(test.T(), "type P struct { x; set(v) => { self.x = v } }; let p = P{x: 1}; p", 3, env)
+
+	s, ok := evaluated.(*object.Struct)
+	if !test.True(ok) {
+		return
+	}
+
+	observer := &fieldChangeObserver{}
+	s.Register(observer)
+
+	testEval(test.T(), "p.x = 2; p.set(3);", 2, env)
+	test.Equal([]string{"x=2", "x=3"}, observer.changes)
+}
+
+func (test *Suite) TestStructErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"type P struct { x }; P{z: 1}", "1:24: P has no field z", 2},
+		{"type P struct { x }; P{}.z", "1:25: P has no field or method z", 2},
+		{"type P struct { x }; let p = P{}; p.z = 1", "1:36: P has no field z", 3},
+		{"type P struct { x; m() => 1 }; let p = P{}; p.m = 2", "1:46: cannot assign to method m of P", 3},
+		{"let a = 1; a.x", "1:13: INTEGER has no field or method x", 2},
+		{"let a = 1; a{x: 1}", "1:13: a is not a struct type, got=INTEGER", 2},
+		{"type P struct { x }; P{x: 1 + true}", "type mismatch: INTEGER + BOOLEAN", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestInterfaces() {
+	const declarations = "type Shape interface { area(); scale(factor int) };" +
+		"type Square struct { side; area() => self.side * self.side; scale(f int) => { self.side = self.side * f } };" +
+		"type Circle struct { r; area() => 3 * self.r * self.r };" +
+		"type Line struct { area() => 0; scale(a string) => 0 };" +
+		"type Any interface {};"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let area = (s Shape) => s.area(); area(Square{side: 3})", "9", 2},
+		{"let grow = (s Shape) => { s.scale(2); s.area() }; let sq = Square{side: 1}; [grow(sq), sq.side]", "[4, 2]", 3},
+		{"let id = (a Any) => a; [id(1), id(Circle{r: 1})]", "[1, Circle{r: 1}]", 2},
+		{"let area = (s Shape, _) => s.area(); area(Square{side: 2}, 1)", "4", 2},
+		{"Shape", "interface Shape", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 5+tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestInterfaceReturnTypes() {
+	const declarations = "type Sized interface { size() int; name() string? };" +
+		"type Box struct { size() int => 1; name() string? => null };" +
+		"type Loose struct { size() => 2; name() => \"loose\" };" +
+		"type Text struct { size() string => \"1\"; name() string? => null };" +
+		"type Label struct { size() int => 1; name() string => \"label\" };" +
+		"let size = (s Sized) => s.size();"
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"size(Box{})", "1"},
+		{"size(Loose{})", "2"},
+		{"size(Text{})", "ERROR: argument s: Text does not implement Sized: method size has signature size() string, expected size() int"},
+		{"size(Label{})", "ERROR: argument s: Label does not implement Sized: method name has signature name() string, expected name() string?"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 7, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestInterfaceErrors() {
+	const declarations = "type Shape interface { area(); scale(factor int) };" +
+		"type Circle struct { r; area() => 3 * self.r * self.r };" +
+		"type Line struct { area() => 0; scale(a string) => 0 };" +
+		"type Curve struct { area() => 0; scale(a, b) => 0 };" +
+		"let area = (s Shape) => s.area();"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"area(Circle{})", "argument s: Circle does not implement Shape: missing method scale(factor int)", 1},
+		{"area(Line{})", "argument s: Line does not implement Shape: method scale has signature scale(a string), expected scale(factor int)", 1},
+		{"area(Curve{})", "argument s: Curve does not implement Shape: method scale has signature scale(a, b), expected scale(factor int)", 1},
+		{"area(1)", "argument s: INTEGER does not implement Shape: missing method area()", 1},
+		{"area(1 + true)", "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 5+tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestParameterAndReturnTypes() {
+	const declarations = "type Point struct { x };" +
+		"type Line struct { a; b };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`let f = (x int, s string, b bool, a array, m map) => [x, s, b, a, m]; f(1, "s", true, [], {})`, "[1, s, true, [], {}]", 2},
+		{"let f = (p Point) => p.x; f(Point{x: 2})", "2", 2},
+		{"let f = (x int?) => x; f(null)", "null", 2},
+		{"let f = (a string) string => a; f(\"s\")", "s", 2},
+		{"let f = <T>(a T) T => a; f(3)", "3", 2},
+		{"let f = (a) int? => a; f(null)", "null", 2},
+		{"let f = (a) Point => a; f(Point{x: 1})", "Point{x: 1}", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 2+tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestParameterAndReturnTypeErrors() {
+	const declarations = "type Point struct { x };" +
+		"type Line struct { a; b };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`let f = (x int) => x; f("s")`, "argument x: STRING is not of type int", 2},
+		{"let f = (s string) => s; f(1)", "argument s: INTEGER is not of type string", 2},
+		{"let f = (a array) => a; f({})", "argument a: MAP is not of type array", 2},
+		{"let f = (p Point) => p; f(Line{})", "argument p: Line is not of type Point", 2},
+		{"let f = (p Point) => p; f(1)", "argument p: INTEGER is not of type Point", 2},
+		{"let f = (x, ...rest int) => rest; f(1, 2, true)", "argument rest: BOOLEAN is not of type int", 2},
+		{"let f = (n int = \"s\") => n; f()", "argument n: STRING is not of type int", 2},
+		{"let f = (a int) string => a; f(1)", "1:81: return value: INTEGER is not of type string", 2},
+		{"let f = (a) int => a; f(null)", "1:74: return value: int is not nullable", 2},
+		{"let f = <T>(a T, b) T => b; f(1, \"s\")", "1:80: return value: type parameter T inferred as INTEGER and STRING", 2},
+		{"type Sized struct { n int }; Sized{n: true}", "1:86: field n: BOOLEAN is not of type int", 2},
+		{"type Sized struct { n int }; let s = Sized{n: 1}; s.n = \"s\"", "1:102: field n: STRING is not of type int", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 2+tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestGenerics() {
+	const declarations = "type Shape interface { area() };" +
+		"type Square struct { side; area() => self.side * self.side };" +
+		"type Box<T> struct { value T; label };" +
+		"let first = <T>(a T, b T) => a;" +
+		"let areaOf = <S Shape>(s S) => s.area();"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`[first(1, 2), first("a", "b"), first(1, 2 + 3)]`, "[1, a, 1]", 1},
+		{"areaOf(Square{side: 3})", "9", 1},
+		{"first(Square{side: 1}, Square{side: 2})", "Square{side: 1}", 1},
+		{`let b = Box{value: 1, label: "x"}; b.value = 2; b.label = true; b`, "Box{value: 2, label: true}", 4},
+		{`let b = Box{value: "a"}; b.value = "b"; b`, "Box{value: b, label: null}", 3},
+		{`[Box{value: 1}, Box{value: "a"}]`, "[Box{value: 1, label: null}, Box{value: a, label: null}]", 1},
+		{"first", "fn<T>(a T, b T) {\nreturn a;\n}", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 5+tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestGenericErrors() {
+	const declarations = "type Shape interface { area() };" +
+		"type Box<T> struct { a T; b T };" +
+		"let first = <T>(a T, b T) => a;" +
+		"let areaOf = <S Shape>(s S) => s.area();"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`first(1, "x")`, "argument b: type parameter T inferred as INTEGER and STRING", 1},
+		{"areaOf(1)", "argument s: type parameter S: INTEGER does not implement Shape: missing method area()", 1},
+		{"Box{a: 1, b: true}", "1:146: field b: type parameter T inferred as INTEGER and BOOLEAN", 1},
+		{`let box = Box{a: 1, b: 2}; box.b = "x"`, "1:166: field b: type parameter T inferred as INTEGER and STRING", 2},
+		{"let f = <T Nope>(a T) => a; f(1)", "1:147: constraint Nope of type parameter T is not an interface", 2},
+		{"type Bad<T Nope> struct { a T }", "1:147: constraint Nope of type parameter T is not an interface", 1},
+		{"type Bad<T> interface { a() }", "1:136: interface Bad can't have type parameters", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), declarations+tt.input, 4+tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestNullable() {
+	const point = "type P struct { x int; y int?; sum() => self.x + (self.y ?? 0) };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"null", "null", 1},
+		{`let x = null; [x ?? 5, 0 ?? 5, null ?? null ?? 3]`, "[5, 0, 3]", 2},
+		{`1 ?? 1 + true`, "1", 1},
+		{`{"a": 1}["b"] ?? 0`, "0", 1},
+		{`let x = null; [x == null, x != null, 1 == null]`, "[true, false, false]", 2},
+		{`"value ${null}"`, "value null", 1},
+		{point + "let p = null; [p?.x, p?.sum(), P{x: 2}?.x, P{x: 2, y: 3}?.sum()]", "[null, null, 2, 5]", 3},
+		{point + "let p = null; p?.x = 1; p", "null", 4},
+		{point + "let p = P{x: 1}; p?.x = 4; p.y = 2; p.y = null; p", "P{x: 4, y: null}", 6},
+		{"let f = (x int?) => x ?? 0; [f(null), f(3)]", "[0, 3]", 2},
+		{point + "let f = (p P?) => if p != null { p.x } else { -1 }; [f(null), f(P{x: 1})]", "[-1, 1]", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestNullErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x int) => x; f(null)", "argument x: int is not nullable", 2},
+		{"type P struct { x int }; P{}", "1:27: field x: int is not nullable", 2},
+		{"type P struct { x int }; P{x: null}", "1:28: field x: int is not nullable", 2},
+		{"type P struct { x int }; let p = P{x: 1}; p.x = null", "1:44: field x: int is not nullable", 3},
+		{"null.x", "1:5: NULL has no field or method x", 1},
+		{"let p = null; p?.x.y", "1:19: NULL has no field or method y", 2},
+		{"[1, 2][2]", "1:7: index 2 out of range for array of length 2", 1},
+		{"[1, 2][-3]", "1:7: index -3 out of range for array of length 2", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestContracts() {
+	const account = "type Account struct { balance int; invariant self.balance > -1;" +
+		"withdraw(amount int) require amount > 0 ensure self.balance < old.amount + self.balance => { self.balance = self.balance - amount } };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x int) require x > 0 ensure result > old.x => x * 2; f(3)", "6", 2},
+		{"let f = (x) ensure result == old.x => { x = x - 1; x + 1 }; f(3)", "3", 2},
+		{"type Acc struct { n int }; let bump = (a Acc) ensure old.a.n + 1 == a.n => { a.n = a.n + 1; a.n }; bump(Acc{n: 1})", "2", 3},
+		{`let push = (m) ensure len(old.m) + 1 == len(m) => { m["k"] = 1; m }; push({})`, "{k: 1}", 2},
+		{"let set = (xs) ensure old.xs[0] == 1 => { xs[0] = 2; xs }; let ys = [1]; set(ys)", "[2]", 3},
+		{"let f = <T>(a T, b T) require a != b ensure result == a => a; f(1, 2)", "1", 2},
+		{account + "let a = Account{balance: 5}; a.withdraw(2); a", "Account{balance: 3}", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestContractErrors() {
+	const account = "type Account struct { balance int; invariant self.balance > -1; withdraw(amount int) require amount > 0 => self.balance = self.balance - amount };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x int) require x > 0 => x; f(0)", "1:38: require x > 0 violated", 2},
+		{"let f = (x) ensure result > old.x => { x = x - 1; x }; f(3)", "1:57: ensure result > old.x violated", 2},
+		{"type Acc struct { n int }; let keep = (a Acc) ensure old.a.n == a.n => { a.n = a.n + 1 }; keep(Acc{n: 1})", "1:95: ensure old.a.n == a.n violated", 3},
+		{account + "Account{balance: -1}", "1:154: invariant self.balance > -1 violated", 2},
+		{account + "let a = Account{balance: 1}; a.withdraw(0)", "1:186: require amount > 0 violated", 3},
+		{account + "let a = Account{balance: 1}; a.withdraw(2)", "1:112: invariant self.balance > -1 violated", 3},
+		{account + "let a = Account{balance: 1}; a.balance = -1; a.balance = 1", "1:177: invariant self.balance > -1 violated", 4},
+		{"let f = (x int) require  (x)>0 => x; f(0)", "1:39: require  (x)>0 violated", 2},
+		{"let f = (s) require s != \"a  b\" => s; f(\"a  b\")", "1:40: require s != \"a  b\" violated", 2},
+		{"let f = (x) ensure result==x => x + 1; f(1)", "1:41: ensure result==x violated", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.ContractError{}, evaluated, tt.input) {
+			test.Equal("CONTRACT ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+
+	evaluated := testEval(test.T(), "let f = (x) require y > 0 => x; f(1)", 2, object.NewEnvironment())
+	test.IsType(&object.EvalError{}, evaluated)
+}
+
+func (test *Suite) TestContractsOff() {
+	CheckContracts = false
+	defer func() { CheckContracts = true }()
+
+	evaluated := testEval(test.T(), "type P struct { x; invariant self.x > 0 }; let f = (x) require x > 0 ensure result > 0 => x; [f(0), P{x: 0}.x]", 3, object.NewEnvironment())
+	test.Equal("[0, 0]", evaluated.Inspect())
+}
+
+func (test *Suite) TestOverloads() {
+	const shapes = "type Shape interface { area() }; type Square struct { side; area() => self.side * self.side };"
+
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x) => 1; let f = (x, y) => 2; [f(0), f(0, 0)]", "[1, 2]", 3},
+		{`let f = (x int) => "int"; let f = (x string) => "string"; let f = (x) => "any"; [f(1), f("a"), f(true)]`, "[int, string, any]", 4},
+		{shapes + `let f = (s Shape) => "shape"; let f = (s Square) => "square"; let f = (s) => "any"; [f(Square{side: 1}), f(1)]`, "[square, any]", 6},
+		{`let f = <T>(a T, b T) => "same"; let f = (a, b) => "different"; [f(1, 2), f(1, "a")]`, "[same, different]", 3},
+		{`let f = (x int?) => "int"; let f = (x) => "any"; [f(null), f(1)]`, "[int, int]", 3},
+		{"let f = (x) => 1; let f = (x) => 2; f(0)", "2", 3},
+		{`let f = (n int) => n; let f = (s string) => f(1) + 1; f("a")`, "2", 3},
+		{"let f = (x) => 1; let f = (x, y) => 2; let g = f; g(1, 2)", "2", 4},
+		{"let f = (x) => 1; let g = () => { let f = (a, b) => 2; f(1, 2) }; [g(), f(1)]", "[2, 1]", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestOverloadErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`let f = (x int) => 1; let f = (x string) => 2; f(true)`, "1:49: no overload of f matches f(BOOLEAN), candidates: f(x int), f(x string)", 3},
+		{"let f = (x) => 1; let f = (x, y) => 2; f()", "1:41: no overload of f matches f(), candidates: f(x), f(x, y)", 3},
+		{"let f = (x int, y) => 1; let f = (x, y int) => 2; f(1, 2)", "1:52: ambiguous call f(INTEGER, INTEGER), candidates: f(x int, y), f(x, y int)", 3},
+		{"let f = (x int?) => 1; let f = (x string?) => 2; f(null)", "1:51: ambiguous call f(NULL), candidates: f(x int?), f(x string?)", 3},
+		{"let f = (x) => 1; let f = (x, y) => 2; f(1 + true)", "type mismatch: INTEGER + BOOLEAN", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestCalls() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let g = (y) => { let z = 1; z = y * 2; z }; let h = (x) => g(x); h(3)", "6", 3},
+		{"let f = (a, b) => a - b; let g = (a, b) => f(b, a); g(1, 3)", "2", 3},
+		{"let n = 0; let inc = () => { n = n + 1; n }; let twice = (x) => x + x; [twice(inc()), n]", "[2, 1]", 4},
+		{"let f = (x, _) => x; f(1, 2)", "1", 2},
+		{"let f = () => 7; f()", "7", 2},
+		{"let a = 1; let f = (x) => x * 2; let b = f(a); a = 2; b", "4", 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestCallErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x, y) => x; f()", "1:23: wrong number of arguments: expected 2, got 0", 2},
+		{"let f = (x) => x; f(1, 2)", "1:20: wrong number of arguments: expected 1, got 2", 2},
+		{"let f = () => 1; f(1)", "1:19: wrong number of arguments: expected 0, got 1", 2},
+		{"let f = (x) => x; f(1 + true)", "type mismatch: INTEGER + BOOLEAN", 2},
+		{"let f = 1; f(2)", "1:13: not a function: INTEGER", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestClosures() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let counter = () => { let n = 0; () => { n = n + 1; n } }; let c = counter(); c(); c(); c()", "3", 5},
+		{"let counter = () => { let n = 0; () => { n = n + 1; n } }; let c = counter(); let d = counter(); c(); c(); [c(), d()]", "[3, 1]", 6},
+		{"let adder = (a) => (b) => a + b; let add2 = adder(2); let add5 = adder(5); [add2(1), add5(1)]", "[3, 6]", 4},
+		{"let y = 1; let f = () => y; let g = () => { let y = 2; f() }; g()", "1", 4},
+		{"let a = 1; let b = a + 1; let f = (a) => b; f(10)", "2", 4},
+		{"let fs = [0, 0, 0]; for i in [1, 2, 3] { fs[i - 1] = () => i * 10 }; [fs[0](), fs[1](), fs[2]()]", "[10, 20, 30]", 3},
+		{"let fs = {}; for i in [1, 2, 3] { fs[i] = () => i }; [fs[1](), fs[3]()]", "[1, 3]", 3},
+		{"let f = (n) => n == 0 ? 1 : n * f(n - 1); f(5)", "120", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestParameters() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x, y?) => y; [f(1), f(1, 2)]", "[null, 2]", 2},
+		{"let f = (x, n = 10) => x + n; [f(1), f(1, 2)]", "[11, 3]", 2},
+		{"let f = (a, b = a * 2) => b; f(3)", "6", 2},
+		{"let f = (x, g = () => m + x, m = 2) => g(); f(1)", "3", 2},
+		{"let f = (m = {}) => { m[len(m)] = 1; len(m) }; f(); f()", "1", 3},
+		{"let f = (...rest) => rest; [f(), f(1, 2)]", "[[], [1, 2]]", 2},
+		{"let f = (first, ...rest int) => len(rest); f(1, 2, 3)", "2", 2},
+		{"let f = (x?, ...rest) => [x, rest]; f()", "[null, []]", 2},
+		{"let f = (x int?) => x; f(null)", "null", 2},
+		{"let f = (x? int) => x; [f(), f(null), f(1)]", "[null, null, 1]", 2},
+		{"let f = (x) => 1; let f = (x, ...rest) => 2; [f(1), f(1, 2)]", "[1, 2]", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestParameterErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (x, y?) => x; f()", "1:24: wrong number of arguments: expected 1 to 2, got 0", 2},
+		{"let f = (x, y = 1) => x; f(1, 2, 3)", "1:27: wrong number of arguments: expected 1 to 2, got 3", 2},
+		{"let f = (x, ...rest) => x; f()", "1:29: wrong number of arguments: expected at least 1, got 0", 2},
+		{"let f = (...rest int) => rest; f(1, null)", "argument rest: int is not nullable", 2},
+		{"let f = (n int = null) => n; f()", "argument n: int is not nullable", 2},
+		{"let f = (n = 1 + true) => n; f()", "type mismatch: INTEGER + BOOLEAN", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestPartialApplication() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let add = (a, b) => a + b; let add2 = add(2); add2(3)", "5", 3},
+		{"let f = (a, b, c) => [a, b, c]; f(1)(2)(3)", "[1, 2, 3]", 2},
+		{"let f = (a, b, c) => [a, b, c]; f(1)(2, 3)", "[1, 2, 3]", 2},
+		{"let sub = (a, b) => a - b; let from10 = sub(10); let minus1 = sub(_, 1); [from10(3), minus1(3)]", "[7, 2]", 4},
+		{"let f = (a, b, c) => [a, b, c]; f(_, 2)(1, 3)", "[1, 2, 3]", 2},
+		{"let f = (a, b, c = 3) => [a, b, c]; f(1)(2)", "[1, 2, 3]", 2},
+		{"let f = (a, b, ...rest) => [a, b, rest]; f(1)(2, 3, 4)", "[1, 2, [3, 4]]", 2},
+		{"let n = 1; let f = (a, b) => a + b + n; let g = f(1); n = 10; g(1)", "12", 5},
+		{`let m = {"a": 1}; let hasKey = has(m); [hasKey("a"), hasKey("b")]`, "[true, false]", 3},
+		{`let inA = has(_, "a"); [inA({"a": 1}), inA({})]`, "[true, false]", 2},
+		{"let f = (x int, y) require x > 0 => x + y; f(1)(2)", "3", 2},
+		{"let same = <T>(a T, b T) => [a, b]; let withOne = same(1); [withOne(2), withOne(3)]", "[[1, 2], [1, 3]]", 3},
+		{"let same = <T>(a T, b T, c T) => [a, b, c]; same(_, \"b\")(\"a\", \"c\")", "[a, b, c]", 2},
+		{"let f = () => {}; let id = (x) => x; id(f())", "null", 3},
+		{"let f = () => {}; let pair = (a, b) => [a, b]; pair(f(), 1)", "[null, 1]", 3},
+		{"let f = () => {}; let pair = (a, b) => [a, b]; pair(_, f())(1)", "[1, null]", 3},
+		{"let f = () => {}; print(f())", "null", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestPartialApplicationErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = (a, b) => a; f(_, 1, 2)", "1:23: wrong number of arguments: expected 2, got 3", 2},
+		{"let f = (a, ...rest) => a; f(_, 1)", "1:29: cannot partially apply variadic parameter rest", 2},
+		{"let f = (x) => 1; let f = (x, y) => 2; f(_, 1)", "1:41: cannot partially apply overloaded function f", 3},
+		{"print(_)", "1:6: cannot partially apply a native function taking any number of arguments", 1},
+		{"let f = (x int, y) => x; f(null)", "argument x: int is not nullable", 2},
+		{`let inA = has(_, "a"); inA()`, "wrong number of arguments: expected 1, got 0", 2},
+		{`let same = <T>(a T, b T) => [a, b]; same(1)("s")`, "argument b: type parameter T inferred as INTEGER and STRING", 2},
+		{`let same = <T>(a T, b T, c T) => [a, b, c]; same(1)(2)("s")`, "argument c: type parameter T inferred as INTEGER and STRING", 2},
+		{`let same = <T>(a T, b T) => [a, b]; let withOne = same(1); withOne(2); withOne("s")`, "argument b: type parameter T inferred as INTEGER and STRING", 4},
+		{`let first = <T>(a T, b) T => b; first(1)("s")`, "1:41: return value: type parameter T inferred as INTEGER and STRING", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestFilterAndReduce() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"filter((x) => x > 1, [1, 2, 3])", "[2, 3]", 1},
+		{"filter((x, i) => i > 0, 5..7)", "[6, 7]", 1},
+		{`filter((v, k) => k == "b", {"a": 1, "b": 2})`, "[2]", 1},
+		{"filter((x) => x > 5, [1, 2])", "[]", 1},
+		{"reduce((acc, x) => acc + x, 0, [1, 2, 3])", "6", 1},
+		{"reduce((acc, x, i) => acc + i, 0, 1..4)", "6", 1},
+		{"reduce((acc, x) => acc + x, 7, [])", "7", 1},
+		{"let big = (x) => x > 1; let bigOnes = filter(big); bigOnes([1, 2, 3])", "[2, 3]", 3},
+		{"let sum = reduce((acc, x) => acc + x, 0); sum([1, 2])", "3", 2},
+		{"let inRange = filter(_, 1..4); inRange((x) => x > 2)", "[3, 4]", 2},
+		{"let f = (x) => x > 1; let f = (x, i) => i > 1; filter(f, [1, 2, 3])", "[3]", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestFilterAndReduceErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"filter((x) => 1, [1])", "1:7: predicate of filter must return BOOLEAN, got=INTEGER", 1},
+		{"filter((x) => x > 1, 5)", `1:7: argument 2 to "filter" must be iterable, got=INTEGER`, 1},
+		{"reduce((acc, x) => acc, 0, true)", `1:7: argument 3 to "reduce" must be iterable, got=BOOLEAN`, 1},
+		{"filter(1, [1])", "1:7: not a function: INTEGER", 1},
+		{"filter((a, b, c) => true, [1])", "1:7: wrong number of arguments: expected 3, got 2", 1},
+		{`filter((x int) => x > 1, ["a"])`, "argument x: STRING is not of type int", 1},
+		{"reduce((acc, x) => acc + x, true, [1])", "type mismatch: BOOLEAN + INTEGER", 1},
+		{"filter((x) => true, [1], 2)", "1:7: expected 2 argument(s) for filter got=3", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestDestructuring() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let f = () => { return 1, 2 }; let a, b = f(); [a, b]", "[1, 2]", 3},
+		{"let a, b = 1, 2; [b, a]", "[2, 1]", 2},
+		{"let [head, ...rest] = [1, 2, 3]; [head, rest]", "[1, [2, 3]]", 2},
+		{"let [head, ...rest] = [1]; rest", "[]", 2},
+		{"let _, b, _ = 1, 2, 3; b", "2", 2},
+		{"let [x, y] = [[1], {}]; [x, y]", "[[1], {}]", 2},
+		{"let a, b = 1, 2; a, b = b, a; [a, b]", "[2, 1]", 3},
+		{"let h = 0; let t = 0; [h, ...t] = [1, 2, 3]; [h, t]", "[1, [2, 3]]", 4},
+		{"let a = 0; let b = 0; let sum = a + b; a, b = 1, 2; sum", "3", 5},
+		{"let a = 0; let f = () => { a, _ = 1, 2 }; f(); a", "1", 4},
+		{"let xs = [1, 2]; let [a, ...rest] = xs; rest[0] = 5; xs", "[1, 2]", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestDestructuringErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"let a, b = [1, 2, 3]", "1:5: wrong number of values to destructure into a, b: expected 2, got 3", 1},
+		{"let [a, b, ...c] = [1]", "1:5: wrong number of values to destructure into [a, b, ...c]: expected at least 2, got 1", 1},
+		{"let a, b = 1", "1:5: cannot destructure INTEGER into a, b", 1},
+		{"a, b = 1, 2", `identifier not found: "a"`, 1},
+		{"let a, b = 1, 1 + true", "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+type countingObserver struct {
+	count int
+}
+
+func (o *countingObserver) Notify(*ast.Expression) {
+	o.count++
+}
+
+func (test *Suite) TestDestructuringObservers() {
+	env := object.NewEnvironment()
+
+	var zero ast.Expression = &ast.IntegerLiteral{Value: 0}
+	observable := object.NewObservable(&zero)
+	observer := &countingObserver{}
+	observable.Register(observer)
+
+	var value ast.Expression = &ast.EvaluatedExpression{Value: observable}
+	env.Set("a", &value)
+
+	testEval(test.T(), "let b = 0; a, b = 1, 2", 2, env)
+	test.Equal(1, observer.count)
+}
+
+func (test *Suite) TestRanges() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"0..3", "0..3", 1},
+		{"0..<10 step 2", "0..<10 step 2", 1},
+		{"len(0..10 step 2)", "6", 1},
+		{"len(0..<10 step 2)", "5", 1},
+		{"len(3..0)", "4", 1},
+		{"len(0..<0)", "0", 1},
+		{"len(0..10 step -1)", "0", 1},
+		{"(0..10 step 3)[2]", "6", 1},
+		{"(10..0)[1]", "9", 1},
+		{"(10..0)[-1]", "0", 1},
+		{"let m = {}; for i in 0..<3 { m[i] = i * i }; m", "{0: 0, 1: 1, 2: 4}", 3},
+		{"let m = {}; for x, i in 5..1 step -2 { m[i] = x }; m", "{0: 5, 1: 3, 2: 1}", 3},
+		{"let n = 0; for i in 0..1000000000 { if i == 3 { break }; n = i }; n", "2", 3},
+		{"let n = 3; let r = 1..n; len(r)", "3", 3},
+		{"len(0..9223372036854775806)", "9223372036854775807", 1},
+		{"len(-9223372036854775807 - 1..<-1)", "9223372036854775807", 1},
+		{"len(9223372036854775807..-9223372036854775807 - 1 step -9223372036854775807 - 1)", "2", 1},
+		{"len(-9223372036854775807 - 1..<-9223372036854775807 - 1)", "0", 1},
+		{"len(-9223372036854775807 - 1..9223372036854775807 step 9223372036854775807)", "3", 1},
+		{"(-9223372036854775807 - 1..9223372036854775807)[9223372036854775807]", "-1", 1},
+		{"(-9223372036854775807 - 1..9223372036854775807 step 2)[9223372036854775807]", "9223372036854775806", 1},
+		{"let n = 0; for i in 0..9223372036854775807 { if i == 3 { break }; n = i }; n", "2", 3},
+		{"(0..9)[2:5]", "2..4", 1},
+		{"(0..9)[::3]", "0..9 step 3", 1},
+		{"(0..9 step 2)[1::2]", "2..6 step 4", 1},
+		{"(0..9)[::-1]", "9..0", 1},
+		{"(0..9)[7:2:-2]", "7..3 step -2", 1},
+		{"(0..9)[4:5]", "4..4", 1},
+		{"(0..9)[5:5]", "0..<0", 1},
+		{"len((0..9223372036854775806)[1:])", "9223372036854775806", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestRangeErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"0..true", "1:2: upper bound of range must be of type integer got=BOOLEAN", 1},
+		{"\"a\"..<3", "1:4: lower bound of range must be of type integer got=STRING", 1},
+		{"0..10 step 0", "1:2: range step cannot be zero", 1},
+		{"(0..2)[3]", "1:7: index 3 out of range for range of length 3", 1},
+		{"(0..2)[-4]", "1:7: index -4 out of range for range of length 3", 1},
+		{"len(0..9223372036854775807)", "length of range 0..9223372036854775807 exceeds the largest integer", 1},
+		{"len(-9223372036854775807 - 1..9223372036854775807)", "length of range -9223372036854775808..9223372036854775807 exceeds the largest integer", 1},
+		{"(0..9223372036854775807)[-1]", "1:25: length of range 0..9223372036854775807 exceeds the largest integer", 1},
+		{"(0..9223372036854775807)[1:]", "1:25: length of range 0..9223372036854775807 exceeds the largest integer", 1},
+		{"(0..9)[2:20]", "1:7: slice bounds out of range [2:20] with length 10", 1},
+		{"(9223372036854775807..-9223372036854775807 - 1 step -9223372036854775807 - 1)[::-1]", "1:78: step of the sliced range exceeds the largest integer", 1},
+		{"5[1:]", "1:2: cannot slice INTEGER", 1},
+		{"5[1]", "1:2: cannot index INTEGER", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestSlicing() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"[1, 2, 3][-2:]", "[2, 3]", 1},
+		{"[1, 2, 3][:-1]", "[1, 2]", 1},
+		{"[1, 2, 3, 4][::-1]", "[4, 3, 2, 1]", 1},
+		{"[1, 2, 3, 4, 5][::2]", "[1, 3, 5]", 1},
+		{"[1, 2, 3, 4, 5][1:-1:2]", "[2, 4]", 1},
+		{"[1, 2, 3, 4, 5][3:0:-1]", "[4, 3, 2]", 1},
+		{"[1, 2, 3][-1::-2]", "[3, 1]", 1},
+		{"[1, 2, 3][3:]", "[]", 1},
+		{"[][:]", "[]", 1},
+		{"[][::-1]", "[]", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestSliceErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"[1, 2, 3][:5]", "1:10: slice bounds out of range [:5] with length 3", 1},
+		{"[1, 2, 3][2:1]", "1:10: slice bounds out of range [2:1] with length 3", 1},
+		{"[1, 2, 3][-4:]", "1:10: slice bounds out of range [-4:] with length 3", 1},
+		{"[1, 2, 3][-5:1:-1]", "1:10: slice bounds out of range [-5:1:-1] with length 3", 1},
+		{"[1, 2, 3][::0]", "1:10: slice step cannot be zero", 1},
+		{"[1, 2, 3][:true]", "upper bound of slice must be of type integer got=BOOLEAN", 1},
+		{"let s = \"a\"; [1, 2, 3][::s]", "step of slice must be of type integer got=STRING", 2},
+		{"[1, 2][-3]", "1:7: index -3 out of range for array of length 2", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestComprehensions() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"[x * x for x in [1, 2, 3]]", "[1, 4, 9]", 1},
+		{"[x for x in 0..10 if x > 5 if x < 8]", "[6, 7]", 1},
+		{"[[x, y] for x in 0..<2 for y in 0..<x + 2]", "[[0, 0], [0, 1], [1, 0], [1, 1], [1, 2]]", 1},
+		{"[x for x in 0..<4 if x > 0 for y in 0..<x if y == 0]", "[1, 2, 3]", 1},
+		{`[v for v, k in {"a": 1, "b": 2}]`, "[1, 2]", 1},
+		{"[i for _, i in [5, 6]]", "[0, 1]", 1},
+		{"[x for x in [] if x]", "[]", 1},
+		{"let xs = [x for x in 0..<3]; xs[0] = 7; xs", "[7, 1, 2]", 3},
+		{"let n = 2; let ys = [x * n for x in 0..<3]; n = 10; ys", "[0, 2, 4]", 4},
+		{"let xs = [1, 2]; xs = [x + 1 for x in xs]; xs", "[2, 3]", 3},
+		{"let fs = [() => x for x in 0..<3]; fs[1]()", "1", 2},
+		{"let x = 7; [x for x in 0..<2]; x", "7", 3},
+		{"[x for x in 0..<3][1:]", "[1, 2]", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestComprehensionErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{"[x for x in 1]", "1:4: cannot iterate over INTEGER", 1},
+		{"[x for x in [1, true] if x > 0]", "type mismatch: BOOLEAN > INTEGER", 1},
+		{"[x + true for x in 0..1]", "type mismatch: INTEGER + BOOLEAN", 1},
+		{"[x for x in [1] for y in x]", "1:17: cannot iterate over INTEGER", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestStrings() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`"a" + "b"`, "ab", 1},
+		{`let s = "a"; s = s + "b" + s; s`, "aba", 3},
+		{`"héllo"[1]`, "é", 1},
+		{`"héllo"[-4]`, "é", 1},
+		{`"héllo"[1:3]`, "él", 1},
+		{`"héllo"[-2:]`, "lo", 1},
+		{`"héllo"[::-1]`, "olléh", 1},
+		{`let s = "abc"; let t = s[1:]; t`, "bc", 3},
+		{`len("héllo")`, "5", 1},
+		{`"a" < "b"`, "true", 1},
+		{`"b" > "ab"`, "true", 1},
+		{`"ab" < "a"`, "false", 1},
+		{`"a" == "a"`, "true", 1},
+		{`let s = "a"; s + "b" == "ab"`, "true", 2},
+		{`"a" != "a"`, "false", 1},
+		{`"a" == 1`, "false", 1},
+		{`switch "b" { case "a": 1 case "b": 2 }`, "2", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestStringErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`"a" - "b"`, "unknown operator: STRING - STRING", 1},
+		{`"ab"[2]`, "1:5: index 2 out of range for string of length 2", 1},
+		{`"ab"["x"]`, "1:5: expected integer for indexing string, got=STRING", 1},
+		{`"héllo"[4:2]`, "1:8: slice bounds out of range [4:2] with length 5", 1},
+		{`"a ${1 + true} b"`, "type mismatch: INTEGER + BOOLEAN", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestEquality() {
+	tests := []struct {
+		input    string
+		expected bool
+		stmts    int
+	}{
+		{`[1, [2, "x"]] == [1, [2, "x"]]`, true, 1},
+		{"[1, 2] == [1]", false, 1},
+		{"[1] != [2]", true, 1},
+		{"[] == []", true, 1},
+		{"[true, null] == [true, null]", true, 1},
+		{"let xs = [1, 2]; let ys = xs[:]; xs == ys", true, 3},
+		{"{} == {}", false, 1},
+		{"let m = {}; m == m", true, 2},
+		{"[{}] == [{}]", false, 1},
+		{"switch [1, 2] { case [1]: false case [1, 2]: true }", true, 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		testBooleanObject(test.T(), evaluated, tt.expected)
+	}
+}
+
+func (test *Suite) TestStringsModule() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`strings.split(",", "a,b,c")`, "[a, b, c]", 1},
+		{`strings.split("", "héj")`, "[h, é, j]", 1},
+		{`let csv = strings.split(","); csv("x,y")`, "[x, y]", 2},
+		{`strings.join("-", ["a", "b"])`, "a-b", 1},
+		{`strings.join("-")([])`, "", 1},
+		{`strings.trim("  hi ")`, "hi", 1},
+		{`strings.upper("héllo")`, "HÉLLO", 1},
+		{`strings.lower("HÉLLO")`, "héllo", 1},
+		{`strings.capitalize("élan")`, "Élan", 1},
+		{`strings.capitalize("")`, "", 1},
+		{`strings.contains("ll", "hello")`, "true", 1},
+		{`strings.startsWith("he")("hello")`, "true", 1},
+		{`strings.endsWith("x", "hello")`, "false", 1},
+		{`strings.replace("l", "L", "hello")`, "heLLo", 1},
+		{`strings.replace("l", "L")("hello")`, "heLLo", 1},
+		{`strings.repeat(3, "ab")`, "ababab", 1},
+		{`strings.padStart(5, "0", "42")`, "00042", 1},
+		{`strings.padEnd(3, ".", "é")`, "é..", 1},
+		{`strings.padEnd(4, ".", "héllo")`, "héllo", 1},
+		{`strings.repeat(9223372036854775807, "")`, "", 1},
+		{`strings.length("héllo")`, "5", 1},
+		{`strings.split(_, "a b")(" ")`, "[a, b]", 1},
+		{`[strings.upper(w) for w in strings.split(" ", "a b")]`, "[A, B]", 1},
+		{`let f = () => strings.upper("a"); f()`, "A", 2},
+		{`let strings = 1; strings`, "1", 2},
+		{`strings`, "module strings", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.NotNil(evaluated, tt.input) {
+			test.Equal(tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestStringsModuleErrors() {
+	tests := []struct {
+		input    string
+		expected string
+		stmts    int
+	}{
+		{`strings.split(",", 1)`, `1:14: argument 2 to "strings.split" must be STRING, got=INTEGER`, 1},
+		{`strings.upper("a", "b")`, "1:14: expected 1 argument(s) for strings.upper got=2", 1},
+		{`strings.join(",", [1])`, `1:13: elements joined by "strings.join" must be STRING, got=INTEGER`, 1},
+		{`strings.repeat(-1, "a")`, `1:15: count of "strings.repeat" cannot be negative, got=-1`, 1},
+		{`strings.padStart(3, "ab", "x")`, `1:17: padding of "strings.padStart" must be a single character, got="ab"`, 1},
+		{`strings.repeat(9223372036854775807, "ab")`, `1:15: result of "strings.repeat" would exceed 1073741824 bytes, got count=9223372036854775807`, 1},
+		{`strings.repeat(536870913, "ab")`, `1:15: result of "strings.repeat" would exceed 1073741824 bytes, got count=536870913`, 1},
+		{`strings.padStart(9223372036854775807, "é", "x")`, `1:17: result of "strings.padStart" would exceed 1073741824 bytes, got width=9223372036854775807`, 1},
+		{`strings.padEnd(9223372036854775807, ".", "")`, `1:15: result of "strings.padEnd" would exceed 1073741824 bytes, got width=9223372036854775807`, 1},
+		{`let pad = strings.padStart(3, "ab"); pad("x")`, `1:41: padding of "strings.padStart" must be a single character, got="ab"`, 2},
+		{`strings.nope`, "1:8: module strings has no member nope", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, tt.stmts, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestMathModule() {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"math.abs(-3)", 3},
+		{"math.abs(3)", 3},
+		{"math.min(2, -1)", -1},
+		{"math.max(2, -1)", 2},
+		{"math.pow(2, 10)", 1024},
+		{"math.pow(-3, 3)", -27},
+		{"math.pow(5, 0)", 1},
+		{"math.sqrt(0)", 0},
+		{"math.sqrt(15)", 3},
+		{"math.sqrt(16)", 4},
+		{"math.sqrt(9223372036854775807)", 3037000499},
+		{"math.clamp(0, 9, 12)", 9},
+		{"math.clamp(0, 9)(-2)", 0},
+		{"math.floor(7, 2)", 3},
+		{"math.floor(-7, 2)", -4},
+		{"math.ceil(7, 2)", 4},
+		{"math.ceil(-7, 2)", -3},
+		{"math.round(5, 2)", 3},
+		{"math.round(-5, 2)", -3},
+		{"math.round(4, 3)", 1},
+		{"math.round(-7, -2)", 4},
+		{"math.div(7, 2)", 3},
+		{"math.div(-7, 2)", -4},
+		{"math.div(-7, -2)", 4},
+		{"math.mod(7, 2)", 1},
+		{"math.mod(-7, 2)", 1},
+		{"math.mod(-7, -2)", 1},
+		{"math.mod(7, -2)", 1},
+		{"math.div(7, -2)", -3},
+		{"math.abs(9223372036854775807)", 9223372036854775807},
+		{"math.abs(-9223372036854775807)", 9223372036854775807},
+		{"math.pow(2, 62)", 4611686018427387904},
+		{"math.pow(-2, 63)", -9223372036854775807 - 1},
+		{"math.pow(-1, 9223372036854775807)", -1},
+		{"math.pow(0, 9223372036854775807)", 0},
+		{"math.pow(9223372036854775807, 1)", 9223372036854775807},
+		{"math.round(9223372036854775807, 2)", 4611686018427387904},
+		{"math.round(-9223372036854775807 - 1, 2)", -4611686018427387904},
+		{"math.round(9223372036854775807, -2)", -4611686018427387904},
+		{"math.round(7, 9223372036854775807)", 0},
+		{"math.round(9223372036854775807, 9223372036854775807)", 1},
+		{"math.round(4611686018427387904, 9223372036854775807)", 1},
+		{"math.round(4611686018427387903, 9223372036854775807)", 0},
+		{"math.round(-4611686018427387904, -9223372036854775807 - 1)", 1},
+		{"math.round(-4611686018427387903, -9223372036854775807 - 1)", 0},
+		{"math.ceil(-9223372036854775807 - 1, 2)", -4611686018427387904},
+		{"math.ceil(9223372036854775807, 2)", 4611686018427387904},
+		{"math.floor(-9223372036854775807 - 1, 3)", -3074457345618258603},
+		{"math.div(-9223372036854775807 - 1, 1)", -9223372036854775807 - 1},
+		{"math.mod(-9223372036854775807 - 1, -1)", 0},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, 1, object.NewEnvironment())
+		if !testIntegerObject(test.T(), evaluated, tt.expected) {
+			test.T().Log(tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestMathModuleErrors() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"math.div(1, 0)", `1:9: division by zero in "math.div"`},
+		{"math.round(1, 0)", `1:11: division by zero in "math.round"`},
+		{"math.pow(2, -1)", `1:9: exponent of "math.pow" cannot be negative, got=-1`},
+		{"math.sqrt(-4)", `1:10: argument of "math.sqrt" cannot be negative, got=-4`},
+		{"math.clamp(9, 0, 1)", `1:11: lower bound of "math.clamp" cannot exceed upper bound, got=9..0`},
+		{`math.abs("a")`, `1:9: argument 1 to "math.abs" must be INTEGER, got=STRING`},
+		{"math.abs(-9223372036854775807 - 1)", `1:9: integer overflow in "math.abs", got=-9223372036854775808`},
+		{"math.pow(2, 64)", `1:9: integer overflow in "math.pow", got=2^64`},
+		{"math.pow(2, 63)", `1:9: integer overflow in "math.pow", got=2^63`},
+		{"math.pow(-3, 9223372036854775807)", `1:9: integer overflow in "math.pow", got=-3^9223372036854775807`},
+		{"math.pow(9223372036854775807, 2)", `1:9: integer overflow in "math.pow", got=9223372036854775807^2`},
+		{"math.div(-9223372036854775807 - 1, -1)", `1:9: integer overflow in "math.div", got=-9223372036854775808 / -1`},
+		{"math.round(-9223372036854775807 - 1, -1)", `1:11: integer overflow in "math.round", got=-9223372036854775808 / -1`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, 1, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestRandomModule() {
+	replay := func(input string) string {
+		stdlib.Seed(42)
+		return testEval(test.T(), input, 1, object.NewEnvironment()).Inspect()
+	}
+
+	for _, input := range []string{
+		"[random.int(1, 6) for _ in 0..<20]",
+		"[random.choice([1, 2, 3]) for _ in 0..<20]",
+		"random.shuffle([1, 2, 3, 4, 5, 6, 7, 8, 9])",
+	} {
+		test.Equal(replay(input), replay(input), input)
+	}
+
+	evaluated := testEval(test.T(), "[x for x in [random.int(-2, 2) for _ in 0..<100] if math.abs(x) > 2]", 1, object.NewEnvironment())
+	test.Equal("[]", evaluated.Inspect())
+
+	evaluated = testEval(test.T(), "random.int(3, 3)", 1, object.NewEnvironment())
+	testIntegerObject(test.T(), evaluated, 3)
+
+	for _, input := range []string{
+		"random.int(0, 9223372036854775807)",
+		"random.int(-9223372036854775807 - 1, 9223372036854775807)",
+		"random.int(-9223372036854775807 - 1, 0)",
+		"random.int(-5, 9223372036854775807)",
+	} {
+		test.IsType(&object.Integer{}, testEval(test.T(), input, 1, object.NewEnvironment()), input)
+	}
+
+	evaluated = testEval(test.T(), "[x for x in [random.int(-1, 9223372036854775807) for _ in 0..<100] if x < -1]", 1, object.NewEnvironment())
+	test.Equal("[]", evaluated.Inspect())
+
+	evaluated = testEval(test.T(), "let xs = [3, 1, 2]; let ys = random.shuffle(xs); [len(ys), xs]", 3, object.NewEnvironment())
+	test.Equal("[3, [3, 1, 2]]", evaluated.Inspect())
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"random.int(2, 1)", `1:11: lower bound of "random.int" cannot exceed upper bound, got=2..1`},
+		{"random.choice([])", `1:14: cannot choose from an empty array in "random.choice"`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(test.T(), tt.input, 1, object.NewEnvironment())
+		if test.IsType(&object.EvalError{}, evaluated, tt.input) {
+			test.Equal("ERROR: "+tt.expected, evaluated.Inspect(), tt.input)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// declareOverload adds the function to the functions declared under the name in the same scope. It reports whether it
+// did, a function with the same signature replaces the declared one like any other redeclaration.
+func declareOverload(name *ast.IdentifierLiteral, fn *object.Function, env *object.Environment) bool {
+	if scope, ok := env.Scope(name.Value); !ok || scope != env {
+		return false
+	}
+
+	declared, ok := declaredFunction(name.Value, env)
+	if !ok {
+		return false
+	}
+
+	switch declared := declared.(type) {
+	case *object.Function:
+		overloads := &object.Overloads{Name: name.Value, Functions: []*object.Function{declared}}
+		overloads.Add(fn)
+		if len(overloads.Functions) == 1 {
+			return false
+		}
+		bindValue(env, name, overloads)
+	case *object.Overloads:
+		declared.Add(fn)
+	}
+
+	return true
+}
+
+// declaredFunction returns the function or overloads bound to the name, only function literals and evaluated values
+// are looked at so nothing is evaluated twice
+func declaredFunction(name string, env *object.Environment) (object.Object, bool) {
+	declaration, _ := env.Get(name)
+
+	switch declaration := (*declaration).(type) {
+	case *ast.FunctionLiteralExpression:
+		return Eval(declaration, env), true
+	case *ast.EvaluatedExpression:
+		switch declaration.Value.(type) {
+		case *object.Function, *object.Overloads:
+			return declaration.Value.(object.Object), true
+		}
+	}
+
+	return nil, false
+}
+
+// resolveOverload picks the function matching the arguments most specifically, the call positioned at tok fails when
+// none or several match equally specific
+func resolveOverload(overloads *object.Overloads, args []object.Object, tok token.Token) (*object.Function, object.Object) {
+	var (
+		matching      []*object.Function
+		specificities [][]int
+	)
+
+	for _, fn := range overloads.Functions {
+		if specificity, ok := matchArguments(fn, args); ok {
+			matching = append(matching, fn)
+			specificities = append(specificities, specificity)
+		}
+	}
+
+	argumentTypes := strings.Join(slice.Map(args, object.TypeName), ", ")
+
+	if len(matching) == 0 {
+		return nil, object.NewEvalErrorObject("%sno overload of %s matches %s(%s), candidates: %s", tokenToPos(tok), overloads.Name, overloads.Name, argumentTypes, overloads.Signatures(overloads.Functions))
+	}
+
+	best := object.MostSpecific(specificities)
+	if len(best) > 1 {
+		ambiguous := slice.Map(best, func(i int) *object.Function { return matching[i] })
+		return nil, object.NewEvalErrorObject("%sambiguous call %s(%s), candidates: %s", tokenToPos(tok), overloads.Name, argumentTypes, overloads.Signatures(ambiguous))
+	}
+
+	return matching[best[0]], nil
+}
+
+// matchArguments returns how specifically each parameter of the function matches the argument passed for it followed by
+// how specifically it matches their number, it reports false when the function doesn't accept the arguments
+func matchArguments(fn *object.Function, args []object.Object) ([]int, bool) {
+	if !ast.TakesArguments(fn.Parameters, len(args)) {
+		return nil, false
+	}
+
+	typeArguments, err := callTypeArguments(fn)
+	if err != nil {
+		return nil, false
+	}
+
+	var specificity []int
+	for i, arg := range args {
+		param, _ := ast.ParameterAt(fn.Parameters, i)
+		s, ok := matchParameter(fn, param, arg, typeArguments)
+		if !ok {
+			return nil, false
+		}
+		specificity = append(specificity, s)
+	}
+
+	return append(specificity, object.MatchesArity(fn.Parameters, len(args))), true
+}
+
+func matchParameter(fn *object.Function, param *ast.Parameter, value object.Object, typeArguments *object.TypeArguments) (int, bool) {
+	if param.Type == nil {
+		return object.MatchesAny, true
+	}
+
+	if value == object.NULL {
+		return object.MatchesExactly, param.AcceptsNull()
+	}
+
+	if typeArguments.IsParameter(param.Type.Value) {
+		if err := typeArguments.Bind(param.Type.Value, value); err != nil {
+			return 0, false
+		}
+
+		for _, tp := range fn.TypeParameters {
+			if tp.Name.Value == param.Type.Value && tp.Constraint != nil {
+				return object.MatchesInterface, true
+			}
+		}
+		return object.MatchesTypeParameter, true
+	}
+
+	if builtin, ok := object.BuiltinType(param.Type.Value); ok {
+		return object.MatchesExactly, value.Type() == builtin
+	}
+
+	if iface, ok := parameterInterface(fn, param); ok {
+		return object.MatchesInterface, iface.Check(value) == nil
+	}
+
+	if structType, ok := lookupStructType(param.Type.Value, fn.Env); ok {
+		s, ok := value.(*object.Struct)
+		return object.MatchesExactly, ok && s.StructType == structType
+	}
+
+	return object.MatchesAny, true
+}
+
+func lookupStructType(name string, env *object.Environment) (*object.StructType, bool) {
+	declaration, ok := env.Get(name)
+	if !ok {
+		return nil, false
+	}
+
+	evaluated, ok := (*declaration).(*ast.EvaluatedExpression)
+	if !ok {
+		return nil, false
+	}
+
+	structType, ok := evaluated.Value.(*object.StructType)
+	return structType, ok
+}
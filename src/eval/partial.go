@@ -0,0 +1,186 @@
+package eval
+
+import (
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+)
+
+// isPlaceholder reports whether the argument is the blank identifier, it leaves the argument out of a partial
+// application, e.g. f(_, 2)
+func isPlaceholder(arg ast.Expression) bool {
+	identifier, ok := arg.(*ast.IdentifierLiteral)
+	return ok && identifier.IsBlank()
+}
+
+// evalArguments evaluates the arguments of a call from left to right, the value of a placeholder is object.PLACEHOLDER
+// and an argument evaluating to nothing, like the call of a function with an empty body, is null
+func evalArguments(args []ast.Expression, env *object.Environment) ([]object.Object, object.Object) {
+	values := make([]object.Object, len(args))
+
+	for i, arg := range args {
+		if isPlaceholder(arg) {
+			values[i] = object.PLACEHOLDER
+			continue
+		}
+
+		value := Eval(arg, env)
+		if interrupts(value) {
+			return nil, value
+		}
+		if value == nil {
+			value = object.NULL
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// isPartial reports whether the call applies the function partially, it does when an argument is a placeholder or some
+// but fewer arguments are given than the function requires. Calling it without arguments is no partial application.
+func isPartial(fn object.Object, args []object.Object) bool {
+	for _, arg := range args {
+		if arg == object.PLACEHOLDER {
+			return true
+		}
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+
+	switch fn := fn.(type) {
+	case *object.Function:
+		least, _ := ast.Arity(fn.Parameters)
+		return len(args) < least
+	case *object.NativeFunc:
+		return len(args) < fn.Arity
+	default:
+		return false
+	}
+}
+
+// partiallyApply binds the given arguments and returns a function taking the ones left out, the placeholders first and
+// then the missing trailing arguments in the order of their parameters
+func partiallyApply(fn object.Object, args []object.Object, tok token.Token) object.Object {
+	switch fn := fn.(type) {
+	case *object.Function:
+		return partiallyApplyFunction(fn, args, tok)
+	case *object.NativeFunc:
+		if fn.Arity == 0 {
+			return object.NewEvalErrorObject("%scannot partially apply a native function taking any number of arguments", tokenToPos(tok))
+		}
+		return partiallyApplyNative(fn, args)
+	case *object.Overloads:
+		return object.NewEvalErrorObject("%scannot partially apply overloaded function %s", tokenToPos(tok), fn.Name)
+	default:
+		return object.NewEvalErrorObject("%snot a function: %s", tokenToPos(tok), fn.Type())
+	}
+}
+
+// partiallyApplyFunction binds the given arguments in an environment enclosing the one of the function, the body of the
+// returned function is the same so it finds them there like any captured variable. The type arguments inferred from
+// them carry over to the calls of the returned function.
+func partiallyApplyFunction(fn *object.Function, args []object.Object, tok token.Token) object.Object {
+	if least, most := ast.Arity(fn.Parameters); most >= 0 && len(args) > most {
+		return object.NewEvalErrorObject("%swrong number of arguments: expected %s, got %d", tokenToPos(tok), arityString(least, most), len(args))
+	}
+
+	env := object.NewEnclosedEnvironment(fn.Env)
+
+	typeArguments, err := callTypeArguments(fn)
+	if err != nil {
+		return err
+	}
+
+	var remaining []*ast.Parameter
+	for i, param := range fn.Parameters {
+		if param.Variadic {
+			if i < len(args) {
+				return object.NewEvalErrorObject("%scannot partially apply variadic parameter %s", tokenToPos(tok), param.Name.Value)
+			}
+			remaining = append(remaining, param)
+			break
+		}
+
+		if i >= len(args) || args[i] == object.PLACEHOLDER {
+			remaining = append(remaining, param)
+			continue
+		}
+
+		if param.Type != nil {
+			if err := checkArgument(fn, param, args[i], typeArguments); err != nil {
+				return object.NewEvalErrorObject("argument %s: %s", param.Name.Value, err)
+			}
+		}
+
+		bindValue(env, param.Name, args[i])
+	}
+
+	return &object.Function{
+		TypeParameters: fn.TypeParameters,
+		TypeArguments:  typeArguments,
+		Parameters:     remaining,
+		ReturnType:     fn.ReturnType,
+		ReturnNullable: fn.ReturnNullable,
+		Requires:       fn.Requires,
+		Ensures:        fn.Ensures,
+		Body:           fn.Body,
+		Env:            env,
+	}
+}
+
+// partiallyApplyNative returns a native function filling the left out arguments with the ones it is called with
+func partiallyApplyNative(fn *object.NativeFunc, args []object.Object) object.Object {
+	bound := make([]object.Object, fn.Arity)
+	if len(args) > fn.Arity {
+		bound = make([]object.Object, len(args))
+	}
+	copy(bound, args)
+
+	var missing []int
+	for i, arg := range bound {
+		if arg == nil || arg == object.PLACEHOLDER {
+			missing = append(missing, i)
+		}
+	}
+
+	fill := func(rest []object.Object) ([]object.Object, object.Object) {
+		if len(rest) < len(missing) {
+			return nil, object.NewEvalErrorObject("wrong number of arguments: expected %d, got %d", len(missing), len(rest))
+		}
+
+		filled := make([]object.Object, len(bound))
+		copy(filled, bound)
+		for i, position := range missing {
+			filled[position] = rest[i]
+		}
+
+		return append(filled, rest[len(missing):]...), nil
+	}
+
+	if fn.Call != nil {
+		return &object.NativeFunc{
+			Arity: len(missing),
+			Call: func(tok token.Token, rest ...object.Object) object.Object {
+				filled, err := fill(rest)
+				if err != nil {
+					return err
+				}
+				return fn.Call(tok, filled...)
+			},
+		}
+	}
+
+	return &object.NativeFunc{
+		Arity: len(missing),
+		Fn: func(rest ...object.Object) object.Object {
+			filled, err := fill(rest)
+			if err != nil {
+				return err
+			}
+			return fn.Fn(filled...)
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package eval
+
+import (
+	"Flow/src/ast"
+	"Flow/src/object"
+)
+
+// evalLetPattern declares the names of the pattern bound to the elements of the value, e.g. let [head, ...rest] = xs
+func evalLetPattern(node *ast.LetStatement, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if interrupts(val) {
+		return val
+	}
+
+	values, err := destructure(node.Pattern, val)
+	if err != nil {
+		return err
+	}
+
+	for i, identifier := range node.Pattern.Identifiers() {
+		bindValue(env, identifier, values[i])
+	}
+
+	return object.NULL
+}
+
+// evalAssignPattern assigns the elements of the value to the names of the pattern, e.g. a, b = b, a. The value is
+// evaluated before any name is assigned and each name is assigned once, so its observers are notified once.
+func evalAssignPattern(pattern *ast.Pattern, right ast.Expression, env *object.Environment) object.Object {
+	val := Eval(right, env)
+	if interrupts(val) {
+		return val
+	}
+
+	values, err := destructure(pattern, val)
+	if err != nil {
+		return err
+	}
+
+	for i, identifier := range pattern.Identifiers() {
+		var value ast.Expression = &ast.EvaluatedExpression{Token: identifier.Token, Value: values[i]}
+		if result := evalAssignIdentifier(identifier, &value, env); isError(result) {
+			return result
+		}
+	}
+
+	return object.NULL
+}
+
+// destructure returns the values for the identifiers of the pattern, the rest takes a new array of the remaining
+// elements. The value has to be an array with an element for every name.
+func destructure(pattern *ast.Pattern, value object.Object) ([]object.Object, object.Object) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return nil, object.NewEvalErrorObject("%scannot destructure %s into %s", tokenToPos(pattern.Token), value.Type(), pattern)
+	}
+
+	least, most := len(pattern.Names), len(pattern.Names)
+	if pattern.Rest != nil {
+		most = -1
+	}
+
+	if len(array.Elements) < least || most >= 0 && len(array.Elements) > most {
+		return nil, object.NewEvalErrorObject("%swrong number of values to destructure into %s: expected %s, got %d", tokenToPos(pattern.Token), pattern, arityString(least, most), len(array.Elements))
+	}
+
+	values := make([]object.Object, least, least+1)
+	copy(values, array.Elements)
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(array.Elements)-least)
+		copy(rest, array.Elements[least:])
+		values = append(values, &object.Array{Elements: rest})
+	}
+
+	return values, nil
+}
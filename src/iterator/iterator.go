@@ -2,6 +2,7 @@ package iterator
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	cerr "Flow/src/error"
 	"Flow/src/metadata"
@@ -18,6 +19,7 @@ type StringIterator interface {
 	PeekN(n int) (rune, *cerr.IterationError)
 	HasNext() bool
 	HasNextN(n int) bool
+	Pos() int
 }
 
 type stringIterator struct {
@@ -34,6 +36,17 @@ func New(sourceFile string) StringIterator {
 	}
 }
 
+// NewAt creates an iterator starting at byte offset pos of the source, line and relative position are counted up to pos
+func NewAt(sourceFile string, pos int) StringIterator {
+	iterator := New(sourceFile).(*stringIterator)
+
+	for iterator.pos < pos {
+		iterator.incrementPosition()
+	}
+
+	return iterator
+}
+
 // Copy make shallow copy of iterator
 func Copy(iterator StringIterator) (StringIterator, error) {
 	if strIterator, ok := iterator.(*stringIterator); ok {
@@ -80,8 +93,10 @@ func (iterator *stringIterator) getNextValidCharacter() (rune, *cerr.IterationEr
 	return iterator.currentChar(), nil
 }
 
+// currentChar decodes the character at the position, characters are UTF-8 encoded and may take several bytes
 func (iterator *stringIterator) currentChar() rune {
-	return []rune(iterator.source[iterator.pos : iterator.pos+1])[0]
+	ch, _ := utf8.DecodeRuneInString(iterator.source[iterator.pos:])
+	return ch
 }
 
 // todo this creates no error yet...
@@ -102,11 +117,17 @@ func (iterator *stringIterator) incrementPosition() {
 	case '\r':
 		iterator.pos++
 	default:
-		iterator.pos++
+		_, size := utf8.DecodeRuneInString(iterator.source[iterator.pos:])
+		iterator.pos += size
 		iterator.relPos++
 	}
 }
 
+// Pos returns the byte offset of the next character
+func (iterator *stringIterator) Pos() int {
+	return iterator.pos
+}
+
 func (iterator *stringIterator) HasNext() bool {
 	return iterator.hasNext(1)
 }
@@ -116,7 +137,16 @@ func (iterator *stringIterator) HasNextN(n int) bool {
 }
 
 func (iterator *stringIterator) hasNext(n int) bool {
-	return iterator.pos+n-1 < len(iterator.source)
+	offset := iterator.pos
+	for i := 0; i < n; i++ {
+		if offset >= len(iterator.source) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(iterator.source[offset:])
+		offset += size
+	}
+
+	return true
 }
 
 func (iterator *stringIterator) Peek() (rune, *cerr.IterationError) {
@@ -135,20 +165,19 @@ func (iterator *stringIterator) peek(n int) (rune, *cerr.IterationError) {
 
 	// count newline characters "\r\n" as single increment
 	offset := iterator.pos
+	var peekChar rune
 	for i := 0; i < n; {
 		if offset+1 > len(iterator.source) {
 			err := cerr.PeekOutOfBoundsError(iterator.source, iterator.line, iterator.pos, n)
 			return 0, &err
 		}
-		if []rune(iterator.source[offset : offset+1])[0] != '\r' {
+		ch, size := utf8.DecodeRuneInString(iterator.source[offset:])
+		if ch != '\r' {
+			peekChar = ch
 			i++
 		}
-		offset++
+		offset += size
 	}
 
-	peekChar := []rune(iterator.source[offset-1 : offset])[0]
-	if peekChar == '\r' {
-		peekChar = '\n'
-	}
 	return peekChar, nil
 }
@@ -127,6 +127,35 @@ func (test *Suite) TestPeek() {
 
 }
 
+func (test *Suite) TestMultiByteCharacters() {
+	iterator := New("hé€x")
+
+	char, err := iterator.PeekN(3)
+	test.expectChar(char, err, '€')
+	test.True(iterator.HasNextN(4))
+	test.False(iterator.HasNextN(5))
+
+	tt := []struct {
+		char        rune
+		pos, relPos int
+	}{
+		{'h', 0, 1},
+		{'é', 1, 2},
+		{'€', 3, 3},
+		{'x', 6, 4},
+	}
+
+	for _, t := range tt {
+		char, metaData, err := iterator.Next()
+		if test.Nil(err) {
+			test.Equal(t.char, char)
+			test.Equal(t.pos, metaData.Pos)
+			test.Equal(t.relPos, metaData.RelPos)
+		}
+	}
+	test.False(iterator.HasNext())
+}
+
 func (test *Suite) TestPeekBounds() {
 	iterator := New("12345")
 
@@ -13,17 +13,41 @@ import (
 // todo metadata and token should become interfaces; place interface in consuming module; data struct adhering these interfaces in own module
 
 type lexer struct {
+	source             string
 	iterator           iterator.StringIterator
 	stringOpen         bool
+	rawStringOpen      bool // string opened by """, it holds no escape sequences and can span multiple lines
 	stringTemplateOpen bool
+	templateClose      rune // closing character of the open template, } for ${...} and > for <...>
+	templateDepth      int  // depth of brackets opened inside the open template
 }
 
 func New(input string) *lexer {
 	i := iterator.New(input)
-	l := &lexer{iterator: i}
+	l := &lexer{source: input, iterator: i}
 	return l
 }
 
+// NewAt creates a lexer starting at byte offset of the input, tokens get the positions they have in the full input
+func NewAt(input string, offset int) *lexer {
+	return &lexer{source: input, iterator: iterator.NewAt(input, offset)}
+}
+
+// Source returns the input the lexer was created with
+func (l *lexer) Source() string {
+	return l.source
+}
+
+// Offset returns the byte offset of the next character to lex
+func (l *lexer) Offset() int {
+	return l.iterator.Pos()
+}
+
+// InString reports whether the lexer is inside a string literal or template, tokens lexed there depend on preceding input
+func (l *lexer) InString() bool {
+	return l.stringOpen || l.rawStringOpen || l.stringTemplateOpen || l.templateDepth != 0
+}
+
 // todo error handling, should we panic?
 
 // NextToken increment position by one token and return it
@@ -34,6 +58,10 @@ func (l *lexer) NextToken() *token.Token {
 		err  *cerr.IterationError
 	)
 
+	if !l.stringOpen || l.stringTemplateOpen {
+		l.skipWhiteSpace()
+	}
+
 	if !l.iterator.HasNext() {
 		return createEOFSymbolToken()
 	}
@@ -109,6 +137,22 @@ func createEOFSymbolToken() *token.Token {
 	return token.New(token.EOF, token.EOF, -1, -1)
 }
 
+// skipWhiteSpace increments position until the next rune is no whitespace, newlines are tokens so they are kept
+func (l *lexer) skipWhiteSpace() {
+	for l.iterator.HasNext() {
+		ch, err := l.iterator.Peek()
+		if err != nil {
+			panic(err)
+		}
+		if ch == '\n' || !unicode.IsSpace(ch) {
+			return
+		}
+		if _, _, err := l.iterator.Next(); err != nil {
+			panic(err)
+		}
+	}
+}
+
 // getNextNonWhiteSpaceCharacter keep incrementing position until non whitespace rune is found
 func (l *lexer) getNextNonWhiteSpaceCharacter() (rune, *metadata.MetaData, *cerr.IterationError) {
 	for {
@@ -128,16 +172,24 @@ func (l *lexer) getNextNonWhiteSpaceCharacter() (rune, *metadata.MetaData, *cerr
 func (l *lexer) isSymbolToken(ch rune, meta *metadata.MetaData) (bool, *token.Token) {
 	newToken := curriedSymbolTokenConstructor(meta)
 
+	if l.stringOpen && l.rawStringOpen && !l.stringTemplateOpen {
+		return true, l.lexRawString(ch, meta)
+	}
+
 	if l.stringOpen && ch != '"' {
-		if ch == '$' && l.isMultiSymbolToken('{') {
-			l.stringTemplateOpen = true
-			return true, newToken(token.STRING_TEMPLATE_OPEN)
-		}
-		if ch == '}' {
-			l.stringTemplateOpen = false
-			return true, newToken(token.RBRACE)
-		}
-		if l.stringTemplateOpen == false {
+		if l.stringTemplateOpen {
+			if ok, tok := l.isTemplateClose(ch, meta); ok {
+				return true, tok
+			}
+		} else {
+			if ch == '$' && l.isMultiSymbolToken('{') {
+				l.openTemplate('}')
+				return true, newToken(token.STRING_TEMPLATE_OPEN)
+			}
+			if ch == '<' {
+				l.openTemplate('>')
+				return true, token.New(token.STRING_INTERPOLATION_OPEN, "<", meta.RelPos, meta.Line)
+			}
 			return true, l.eatString(ch, meta)
 		}
 	}
@@ -176,9 +228,27 @@ func (l *lexer) isSymbolToken(ch rune, meta *metadata.MetaData) (bool, *token.To
 	case ';':
 		return true, newToken(token.SEMICOLON)
 	case '?':
-		return true, newToken(token.QUESTION)
+		switch {
+		case l.isMultiSymbolToken('.'):
+			return true, newToken(token.SAFE_DOT)
+		case l.isMultiSymbolToken('?'):
+			return true, newToken(token.COALESCE)
+		default:
+			return true, newToken(token.QUESTION)
+		}
 	case ':':
 		return true, newToken(token.COLON)
+	case '.':
+		switch {
+		case l.isMultiSymbolToken('.', '.'):
+			return true, newToken(token.ELLIPSIS)
+		case l.isMultiSymbolToken('.', '<'):
+			return true, newToken(token.RANGE_EXCLUSIVE)
+		case l.isMultiSymbolToken('.'):
+			return true, newToken(token.RANGE)
+		default:
+			return true, newToken(token.DOT)
+		}
 	case '\n':
 		return true, newToken(token.NEWLINE)
 	case '(':
@@ -194,6 +264,11 @@ func (l *lexer) isSymbolToken(ch rune, meta *metadata.MetaData) (bool, *token.To
 	case ']':
 		return true, newToken(token.RBRACKET)
 	case '"':
+		if !l.stringOpen && l.isMultiSymbolToken('"', '"') {
+			l.stringOpen = true
+			l.rawStringOpen = true
+			return true, newToken(token.RAW_STRING_DELIMITER)
+		}
 		l.stringOpen = !l.stringOpen
 		return true, newToken(token.STRING_DELIMITER)
 	default:
@@ -201,30 +276,82 @@ func (l *lexer) isSymbolToken(ch rune, meta *metadata.MetaData) (bool, *token.To
 	}
 }
 
+func (l *lexer) openTemplate(close rune) {
+	l.stringTemplateOpen = true
+	l.templateClose = close
+	l.templateDepth = 0
+}
+
+// isTemplateClose checks whether ch closes the open string template, brackets opened inside the template have to be
+// closed first so "<(a > b)>" only closes on the last >
+func (l *lexer) isTemplateClose(ch rune, meta *metadata.MetaData) (bool, *token.Token) {
+	switch ch {
+	case '(', '[', '{':
+		l.templateDepth++
+	case ')', ']':
+		l.templateDepth--
+	case '}', '>':
+		if l.templateDepth == 0 && ch == l.templateClose {
+			l.stringTemplateOpen = false
+			if ch == '>' {
+				return true, token.New(token.STRING_INTERPOLATION_CLOSE, ">", meta.RelPos, meta.Line)
+			}
+			return true, token.NewSymbol(token.RBRACE, meta.RelPos, meta.Line)
+		}
+		if ch == '}' {
+			l.templateDepth--
+		}
+	}
+
+	return false, nil
+}
+
+// lexRawString lexes the inside of a raw string, only """ and ${ are special inside a raw string
+func (l *lexer) lexRawString(ch rune, meta *metadata.MetaData) *token.Token {
+	if ch == '"' && l.isMultiSymbolToken('"', '"') {
+		l.stringOpen = false
+		l.rawStringOpen = false
+		return token.NewSymbol(token.RAW_STRING_DELIMITER, meta.RelPos, meta.Line)
+	}
+
+	if ch == '$' && l.isMultiSymbolToken('{') {
+		l.openTemplate('}')
+		return token.NewSymbol(token.STRING_TEMPLATE_OPEN, meta.RelPos, meta.Line)
+	}
+
+	return l.eatRawString(ch, meta)
+}
+
+// eatString eats string characters until the string closes or a template opens, the literal is the raw source
+// so escape sequences are kept as is and an escaped character never closes the string or opens a template
 func (l *lexer) eatString(ch rune, meta *metadata.MetaData) *token.Token {
 	t := token.Token{
 		Type:    token.STRING_CHARACTERS,
-		Pos:     meta.Pos,
+		Pos:     meta.RelPos,
 		Line:    meta.Line,
 		Literal: string(ch),
 	}
 
+	escaped := ch == '\\'
+
 	for l.iterator.HasNext() {
 		ch, err := l.iterator.Peek()
 		if err != nil {
 			panic(err)
 		}
-		if ch == '"' {
-			return &t
-		}
-		if ch == '$' {
-			nextCh, err := l.iterator.PeekN(2)
-			if err != nil {
-				panic(err)
-			}
-			if nextCh == '{' {
+		if !escaped {
+			if ch == '"' || ch == '<' {
 				return &t
 			}
+			if ch == '$' && l.iterator.HasNextN(2) {
+				nextCh, err := l.iterator.PeekN(2)
+				if err != nil {
+					panic(err)
+				}
+				if nextCh == '{' {
+					return &t
+				}
+			}
 		}
 
 		ch, _, err = l.iterator.Next()
@@ -232,13 +359,25 @@ func (l *lexer) eatString(ch rune, meta *metadata.MetaData) *token.Token {
 			panic(err)
 		}
 		t.Literal += string(ch)
+		escaped = !escaped && ch == '\\'
 	}
 
 	return &t
 }
 
+// eatRawString eats raw string characters until the raw string closes or a template opens
+func (l *lexer) eatRawString(ch rune, meta *metadata.MetaData) *token.Token {
+	literal := []rune{ch}
+
+	l.appendLiteralUntil(&literal, func(ch rune) bool {
+		return (ch == '"' && l.isNext('"', '"', '"')) || (ch == '$' && l.isNext('$', '{'))
+	})
+
+	return token.New(token.STRING_CHARACTERS, string(literal), meta.RelPos, meta.Line)
+}
+
 func (l *lexer) isStringLiteral(ch rune, meta *metadata.MetaData) (bool, *token.Token) {
-	if !unicode.IsLetter(ch) {
+	if !(unicode.IsLetter(ch) || ch == '_') {
 		return false, token.NewSymbol(token.UNKNOWN, meta.RelPos, meta.Line)
 	}
 
@@ -246,7 +385,7 @@ func (l *lexer) isStringLiteral(ch rune, meta *metadata.MetaData) (bool, *token.
 	literal = append(literal, ch)
 
 	l.appendLiteralUntil(&literal, func(ch rune) bool {
-		return !(unicode.IsLetter(ch) || unicode.IsDigit(ch))
+		return !(unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_')
 	})
 
 	return true, token.New(token.IDENT, string(literal), meta.RelPos, meta.Line)
@@ -293,8 +432,28 @@ func curriedSymbolTokenConstructor(meta *metadata.MetaData) func(t token.Type) *
 	}
 }
 
+// isMultiSymbolToken checks whether the next characters match chs, only on a match the characters are consumed
 func (l *lexer) isMultiSymbolToken(chs ...rune) bool {
+	if !l.isNext(chs...) {
+		return false
+	}
+
+	for range chs {
+		if _, _, err := l.iterator.Next(); err != nil {
+			panic(err) // todo error handling
+		}
+	}
+
+	return true
+}
+
+// isNext checks whether the next characters match chs without consuming them
+func (l *lexer) isNext(chs ...rune) bool {
 	for i, ch := range chs {
+		if !l.iterator.HasNextN(i + 1) {
+			return false
+		}
+
 		p, err := l.iterator.PeekN(i + 1)
 		if err != nil {
 			panic(err) // todo error handling
@@ -302,10 +461,6 @@ func (l *lexer) isMultiSymbolToken(chs ...rune) bool {
 		if p != ch {
 			return false
 		}
-
-		if _, _, err = l.iterator.Next(); err != nil {
-			panic(err) // todo error handling
-		}
 	}
 
 	return true
@@ -20,7 +20,7 @@ func TestClientTestSuite(t *testing.T) {
 }
 
 func (test *Suite) TestSymbolToken() {
-	l := New("== ") // todo remove trailing space when fixed
+	l := New("==")
 	t := l.NextToken()
 	test.NotNil(t)
 
@@ -33,7 +33,7 @@ func (test *Suite) TestSymbolToken() {
 }
 
 func (test *Suite) TestStringLiteral() {
-	l := New("al  ") // todo remove trailing space when fixed
+	l := New("al")
 	t := l.NextToken()
 	test.NotNil(t)
 
@@ -331,6 +331,184 @@ func (test *Suite) TestEatString_WithInterpolation() {
 	}
 }
 
+func (test *Suite) TestEatString_WithEscapes() {
+	l := New(`"say \"hi\" \<x> \${y}\\";`)
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.STRING_DELIMITER, "\""},
+		{token.STRING_CHARACTERS, `say \"hi\" \<x> \${y}\\`},
+		{token.STRING_DELIMITER, "\""},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestEatString_WithAngleInterpolation() {
+	l := New(`"v <(a > b)> }";`)
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.STRING_DELIMITER, "\""},
+		{token.STRING_CHARACTERS, "v "},
+		{token.STRING_INTERPOLATION_OPEN, "<"},
+		{token.LPAREN, "("},
+		{token.IDENT, "a"},
+		{token.GT, ">"},
+		{token.IDENT, "b"},
+		{token.RPAREN, ")"},
+		{token.STRING_INTERPOLATION_CLOSE, ">"},
+		{token.STRING_CHARACTERS, " }"},
+		{token.STRING_DELIMITER, "\""},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestEatRawString() {
+	l := New(`"""
+  say "hi" <b> \n
+  ${name}""";`)
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.RAW_STRING_DELIMITER, "\"\"\""},
+		{token.STRING_CHARACTERS, "\n  say \"hi\" <b> \\n\n  "},
+		{token.STRING_TEMPLATE_OPEN, "${"},
+		{token.IDENT, "name"},
+		{token.RBRACE, "}"},
+		{token.RAW_STRING_DELIMITER, "\"\"\""},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestIdentifiersWithUnderscores() {
+	l := New("let snake_case = _private1 + _; (_, i_2)")
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "snake_case"},
+		{token.ASSIGN, "="},
+		{token.IDENT, "_private1"},
+		{token.PLUS, "+"},
+		{token.BLANK, "_"},
+		{token.SEMICOLON, ";"},
+		{token.LPAREN, "("},
+		{token.BLANK, "_"},
+		{token.COMMA, ","},
+		{token.IDENT, "i_2"},
+		{token.RPAREN, ")"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestNullableOperators() {
+	l := New("p?.x ?? null; c ? 1 : 2; (x int?, ...y)")
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.IDENT, "p"},
+		{token.SAFE_DOT, "?."},
+		{token.IDENT, "x"},
+		{token.COALESCE, "??"},
+		{token.NULL, "null"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "c"},
+		{token.QUESTION, "?"},
+		{token.INT, "1"},
+		{token.COLON, ":"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.IDENT, "int"},
+		{token.QUESTION, "?"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "y"},
+		{token.RPAREN, ")"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestRangeOperators() {
+	l := New("0..10 step 2; 0..<n; a.b")
+	tests := []struct {
+		expectedToken   token.Type
+		expectedLiteral string
+	}{
+		{token.INT, "0"},
+		{token.RANGE, ".."},
+		{token.INT, "10"},
+		{token.IDENT, "step"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "0"},
+		{token.RANGE_EXCLUSIVE, "..<"},
+		{token.IDENT, "n"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.DOT, "."},
+		{token.IDENT, "b"},
+		{token.EOF, "EOF"},
+	}
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+		test.Equal(tt.expectedToken, tok.Type)
+		test.Equal(tt.expectedLiteral, tok.Literal)
+	}
+}
+
+func (test *Suite) TestNewAt() {
+	l := NewAt("let a = 1;\n  let b ", 13)
+
+	tok := l.NextToken()
+	test.Equal(token.Token{Type: token.LET, Literal: "let", Pos: 3, Line: 2}, *tok)
+	test.Equal(16, l.Offset())
+
+	tok = l.NextToken()
+	test.Equal(token.Token{Type: token.IDENT, Literal: "b", Pos: 7, Line: 2}, *tok)
+	test.Equal(token.Type(token.EOF), l.NextToken().Type)
+}
+
 func (test *Suite) TestEOF() {
 	l := New("0")
 
@@ -0,0 +1,21 @@
+package object
+
+import "fmt"
+
+const (
+	CONTRACT_ERROR_OBJ = "CONTRACT_ERROR"
+)
+
+// ContractError is the violation of a require, ensure or invariant clause, it ends the evaluation like EvalError
+type ContractError struct {
+	Clause    string // the violated clause as written, e.g. require x > 0
+	Line, Pos int    // position of the call or assignment checking the clause
+}
+
+func (e *ContractError) Type() ObjectType {
+	return CONTRACT_ERROR_OBJ
+}
+
+func (e *ContractError) Inspect() string {
+	return fmt.Sprintf("CONTRACT ERROR: %d:%d: %s violated", e.Line, e.Pos, e.Clause)
+}
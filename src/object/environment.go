@@ -2,6 +2,7 @@ package object
 
 import (
 	"fmt"
+	"sort"
 
 	"Flow/src/ast"
 )
@@ -32,6 +33,41 @@ func (e *Environment) Get(name string) (*ast.Expression, bool) {
 	return obj, ok
 }
 
+// Scope returns the environment declaring name, which is this environment or one of its outer environments
+func (e *Environment) Scope(name string) (*Environment, bool) {
+	if _, ok := e.store[name]; ok {
+		return e, true
+	}
+
+	if e.outer != nil {
+		return e.outer.Scope(name)
+	}
+
+	return nil, false
+}
+
+// Encloses reports whether inner is this environment or nested in it
+func (e *Environment) Encloses(inner *Environment) bool {
+	for ; inner != nil; inner = inner.outer {
+		if inner == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Names returns the sorted names declared in this environment, without those of its outer environments
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func (e *Environment) mustGet(name string) *ast.Expression {
 	val, ok := e.Get(name)
 	if !ok {
@@ -56,13 +92,23 @@ func (e *Environment) SubstituteReferences(node ast.Expression, name *string) as
 				return node
 			}
 			val, ok := e.Get(node.Value)
+			if _, isModule := LookupModule(node.Value); !ok && isModule { // modules are shadowed by variables
+				return node
+			}
 			if !ok {
 				panic(fmt.Sprintf("could not find identifier %s in closure or outer closures", node.Value))
 			}
-			if e.outer != nil {
-				return e.outer.SubstituteReferences(*val, nil)
+			// the stored expression refers to the variables visible where it is declared instead of those of e, its own
+			// name refers to the variable it shadows
+			declaring, _ := e.Scope(node.Value)
+			if declaring.outer != nil {
+				shadowed := declaring.outer.SubstituteReferences(*val, &node.Value)
+				return declaring.SubstituteReferences(shadowed, nil)
+			}
+			if refersTo(*val, node.Value) { // there is no variable to shadow at the top level
+				return *val
 			}
-			return *val
+			return declaring.SubstituteReferences(*val, nil)
 		}
 		return node
 	case *ast.PrefixExpression:
@@ -79,6 +125,10 @@ func (e *Environment) SubstituteReferences(node ast.Expression, name *string) as
 				right = e.SubstituteReferences(node.Right, &identifier.Value)
 			} else if _, ok := node.Left.(*ast.IndexExpression); ok {
 				right = e.SubstituteReferences(node.Right, nil)
+			} else if _, ok := node.Left.(*ast.FieldAccessExpression); ok {
+				right = e.SubstituteReferences(node.Right, nil)
+			} else if _, ok := node.Left.(*ast.Pattern); ok {
+				right = e.SubstituteReferences(node.Right, nil)
 			} else {
 				panic(fmt.Sprintf("expected left hand side of assignment expression to be identifier literal, got=%T", node.Left))
 			}
@@ -100,6 +150,19 @@ func (e *Environment) SubstituteReferences(node ast.Expression, name *string) as
 			Left:  left,
 			Lower: node.Lower,
 			Upper: node.Upper,
+			Step:  node.Step,
+		}
+	case *ast.RangeExpression:
+		var step ast.Expression
+		if node.Step != nil {
+			step = e.SubstituteReferences(node.Step, name)
+		}
+		return &ast.RangeExpression{
+			Token:     node.Token,
+			Lower:     e.SubstituteReferences(node.Lower, name),
+			Upper:     e.SubstituteReferences(node.Upper, name),
+			Exclusive: node.Exclusive,
+			Step:      step,
 		}
 	case *ast.IndexExpression:
 		left := e.SubstituteReferences(node.Left, name)
@@ -112,3 +175,17 @@ func (e *Environment) SubstituteReferences(node ast.Expression, name *string) as
 		return node
 	}
 }
+
+// refersTo reports whether the expression refers to the name
+func refersTo(node ast.Expression, name string) bool {
+	found := false
+
+	ast.Inspect(node, func(node ast.Node) bool {
+		if identifier, ok := node.(*ast.IdentifierLiteral); ok && identifier.Value == name {
+			found = true
+		}
+		return !found
+	})
+
+	return found
+}
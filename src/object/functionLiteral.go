@@ -10,9 +10,15 @@ import (
 const FUNCTION_OBJ = "FUNCTION"
 
 type Function struct {
-	Parameters []*ast.IdentifierLiteral
-	Body       *ast.BlockStatement
-	Env        *Environment
+	TypeParameters []*ast.TypeParameter
+	TypeArguments  *TypeArguments // inferred from the arguments bound by partial application, nil otherwise
+	Parameters     []*ast.Parameter
+	ReturnType     *ast.IdentifierLiteral // nil when the return type is omitted
+	ReturnNullable bool
+	Requires       []*ast.Clause
+	Ensures        []*ast.Clause
+	Body           *ast.BlockStatement
+	Env            *Environment
 }
 
 func (f *Function) Type() ObjectType {
@@ -28,6 +34,7 @@ func (f *Function) Inspect() string {
 	}
 
 	out.WriteString("fn")
+	out.WriteString(ast.TypeParameterList(f.TypeParameters))
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") {\n")
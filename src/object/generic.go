@@ -0,0 +1,76 @@
+package object
+
+import "fmt"
+
+// TypeParameter is a type parameter of a generic function or struct, the constraint is nil when any type is allowed
+type TypeParameter struct {
+	Name       string
+	Constraint *InterfaceType
+}
+
+// TypeArguments infers the type arguments of a generic function or struct from the values passed for its type
+// parameters, every value passed for the same type parameter has to be of the same type
+type TypeArguments struct {
+	parameters map[string]*TypeParameter
+	inferred   map[string]string
+}
+
+func NewTypeArguments(typeParameters []*TypeParameter) *TypeArguments {
+	ta := &TypeArguments{parameters: make(map[string]*TypeParameter), inferred: make(map[string]string)}
+	for _, tp := range typeParameters {
+		ta.parameters[tp.Name] = tp
+	}
+
+	return ta
+}
+
+// Copy returns type arguments continuing the inference from the type arguments inferred so far, inferring more from
+// the copy leaves these unchanged
+func (ta *TypeArguments) Copy() *TypeArguments {
+	c := &TypeArguments{parameters: ta.parameters, inferred: make(map[string]string, len(ta.inferred))}
+	for name, inferred := range ta.inferred {
+		c.inferred[name] = inferred
+	}
+
+	return c
+}
+
+// IsParameter reports whether the type name refers to one of the type parameters
+func (ta *TypeArguments) IsParameter(typeName string) bool {
+	_, ok := ta.parameters[typeName]
+	return ok
+}
+
+// Bind infers the type argument of the type parameter named by typeName from the value. It fails when the type of the
+// value differs from the type argument inferred before or doesn't implement the constraint. Type names not referring
+// to a type parameter and null values are ignored.
+func (ta *TypeArguments) Bind(typeName string, value Object) error {
+	tp, ok := ta.parameters[typeName]
+	if !ok || value == NULL {
+		return nil
+	}
+
+	actual := TypeName(value)
+	if inferred, ok := ta.inferred[tp.Name]; ok && inferred != actual {
+		return fmt.Errorf("type parameter %s inferred as %s and %s", tp.Name, inferred, actual)
+	}
+
+	if tp.Constraint != nil {
+		if err := tp.Constraint.Check(value); err != nil {
+			return fmt.Errorf("type parameter %s: %w", tp.Name, err)
+		}
+	}
+
+	ta.inferred[tp.Name] = actual
+
+	return nil
+}
+
+// TypeName is the name of the type of the value, the name of its struct type for structs
+func TypeName(value Object) string {
+	if s, ok := value.(*Struct); ok {
+		return s.StructType.Name
+	}
+
+	return string(value.Type())
+}
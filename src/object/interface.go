@@ -0,0 +1,46 @@
+package object
+
+import (
+	"fmt"
+
+	"Flow/src/ast"
+)
+
+const INTERFACE_TYPE_OBJ = "INTERFACE_TYPE"
+
+// InterfaceType is the value of an interface type declaration, values conform to it structurally
+type InterfaceType struct {
+	Name    string
+	Methods []*ast.Signature
+}
+
+func (it *InterfaceType) Type() ObjectType {
+	return INTERFACE_TYPE_OBJ
+}
+
+func (it *InterfaceType) Inspect() string {
+	return "interface " + it.Name
+}
+
+// Check returns an error naming the first method of the interface the value doesn't implement, only structs have
+// methods so other values only implement interfaces without methods
+func (it *InterfaceType) Check(value Object) error {
+	methods := make(map[string]*ast.Signature)
+
+	if s, ok := value.(*Struct); ok {
+		for name, method := range s.StructType.Methods {
+			methods[name] = &ast.Signature{
+				Name:           &ast.IdentifierLiteral{Value: name},
+				Parameters:     method.Parameters,
+				ReturnType:     method.ReturnType,
+				ReturnNullable: method.ReturnNullable,
+			}
+		}
+	}
+
+	if mismatch := ast.Implements(it.Methods, methods); mismatch != "" {
+		return fmt.Errorf("%s does not implement %s: %s", TypeName(value), it.Name, mismatch)
+	}
+
+	return nil
+}
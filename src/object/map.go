@@ -0,0 +1,122 @@
+package object
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const MAP_OBJ = "MAP"
+
+// MapKey identifies a key of a map, keys are equal when their type and value are equal
+type MapKey struct {
+	Type  ObjectType
+	Value string
+}
+
+// Hashable is implemented by the objects usable as key of a map
+type Hashable interface {
+	Object
+	MapKey() MapKey
+}
+
+func (i *Integer) MapKey() MapKey {
+	return MapKey{Type: i.Type(), Value: strconv.FormatInt(i.Value, 10)}
+}
+
+func (s *String) MapKey() MapKey {
+	return MapKey{Type: s.Type(), Value: s.Value}
+}
+
+func (b *Boolean) MapKey() MapKey {
+	return MapKey{Type: b.Type(), Value: strconv.FormatBool(b.Value)}
+}
+
+type MapPair struct {
+	Key   Hashable
+	Value Object
+}
+
+// Map holds its pairs in insertion order so iterating and printing a map is deterministic, assigning to an existing
+// key keeps its position
+type Map struct {
+	pairs map[MapKey]*MapPair
+	order []MapKey
+}
+
+func NewMap() *Map {
+	return &Map{pairs: make(map[MapKey]*MapPair)}
+}
+
+func (m *Map) Type() ObjectType {
+	return MAP_OBJ
+}
+
+func (m *Map) Inspect() string {
+	var out bytes.Buffer
+
+	var pairs []string
+	for _, pair := range m.Pairs() {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (m *Map) Get(key Hashable) (Object, bool) {
+	pair, ok := m.pairs[key.MapKey()]
+	if !ok {
+		return nil, false
+	}
+
+	return pair.Value, true
+}
+
+func (m *Map) Set(key Hashable, value Object) {
+	mapKey := key.MapKey()
+
+	if pair, ok := m.pairs[mapKey]; ok {
+		pair.Value = value
+		return
+	}
+
+	m.pairs[mapKey] = &MapPair{Key: key, Value: value}
+	m.order = append(m.order, mapKey)
+}
+
+// Delete removes the pair of key, it reports whether the map held the key
+func (m *Map) Delete(key Hashable) bool {
+	mapKey := key.MapKey()
+
+	if _, ok := m.pairs[mapKey]; !ok {
+		return false
+	}
+
+	delete(m.pairs, mapKey)
+	for i, k := range m.order {
+		if k == mapKey {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (m *Map) Len() int {
+	return len(m.order)
+}
+
+// Pairs returns the pairs in insertion order
+func (m *Map) Pairs() []MapPair {
+	pairs := make([]MapPair, 0, len(m.order))
+	for _, key := range m.order {
+		pairs = append(pairs, *m.pairs[key])
+	}
+
+	return pairs
+}
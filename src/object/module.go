@@ -0,0 +1,37 @@
+package object
+
+import "fmt"
+
+const MODULE_OBJ = "MODULE"
+
+// Module is a named set of native members like the strings module, its members are accessed like fields e.g.
+// strings.upper. Modules are registered once and shared, so their members must not be changed.
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+
+func (m *Module) Inspect() string {
+	return "module " + m.Name
+}
+
+var modules = map[string]*Module{}
+
+// RegisterModule makes the module available by its name, variables of the same name shadow it. Registering a name
+// twice is a programming error so it panics.
+func RegisterModule(module *Module) {
+	if _, ok := modules[module.Name]; ok {
+		panic(fmt.Sprintf("module %s registered twice", module.Name))
+	}
+	modules[module.Name] = module
+}
+
+// LookupModule returns the module registered by the name
+func LookupModule(name string) (*Module, bool) {
+	module, ok := modules[name]
+	return module, ok
+}
@@ -1,11 +1,19 @@
 package object
 
+import "Flow/src/token"
+
 type NativeFunction func(args ...Object) Object
 
+// NativeCall is a native function knowing the token of its call, its errors and the calls of the functions passed to it
+// are positioned at tok
+type NativeCall func(tok token.Token, args ...Object) Object
+
 const NATIVE_FN_OBJ = "NATIVE_FN"
 
 type NativeFunc struct {
-	Fn NativeFunction
+	Fn    NativeFunction
+	Call  NativeCall // set instead of Fn by native functions positioning their errors or calling functions
+	Arity int        // calling it with fewer arguments applies it partially, 0 when it takes any number of arguments
 }
 
 func (f *NativeFunc) Type() ObjectType {
@@ -2,6 +2,7 @@ package object
 
 import (
 	"fmt"
+	"unicode/utf8"
 )
 
 // todo might not be the right package to place this
@@ -11,10 +12,27 @@ var Builtins = map[string]*NativeFunc{
 		Fn: func(args ...Object) Object {
 			return flowLen(args...)
 		},
+		Arity: 1,
 	},
 	"print": {
 		Fn: print,
 	},
+	"keys": {
+		Fn:    keys,
+		Arity: 1,
+	},
+	"values": {
+		Fn:    values,
+		Arity: 1,
+	},
+	"has": {
+		Fn:    has,
+		Arity: 2,
+	},
+	"delete": {
+		Fn:    deleteKey,
+		Arity: 2,
+	},
 }
 
 func flowLen(args ...Object) Object {
@@ -23,9 +41,17 @@ func flowLen(args ...Object) Object {
 	}
 	switch arg := args[0].(type) {
 	case *String:
-		return &Integer{Value: int64(len(arg.Value))}
+		return &Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 	case *Array:
 		return &Integer{Value: int64(len(arg.Elements))}
+	case *Map:
+		return &Integer{Value: int64(arg.Len())}
+	case *Range:
+		length, ok := arg.Len()
+		if !ok {
+			return NewEvalErrorObject("length of range %s exceeds the largest integer", arg.Inspect())
+		}
+		return &Integer{Value: length}
 	default:
 		return NewEvalErrorObject(fmt.Sprintf("argument to \"len\" not supported, got=%T", args[0]))
 	}
@@ -38,3 +64,81 @@ func print(args ...Object) Object {
 
 	return NULL
 }
+
+// keys returns the keys of a map in insertion order
+func keys(args ...Object) Object {
+	m, err := mapArgument("keys", 1, args)
+	if err != nil {
+		return err
+	}
+
+	var elements []Object
+	for _, pair := range m.Pairs() {
+		elements = append(elements, pair.Key)
+	}
+
+	return &Array{Elements: elements}
+}
+
+// values returns the values of a map in insertion order of their keys
+func values(args ...Object) Object {
+	m, err := mapArgument("values", 1, args)
+	if err != nil {
+		return err
+	}
+
+	var elements []Object
+	for _, pair := range m.Pairs() {
+		elements = append(elements, pair.Value)
+	}
+
+	return &Array{Elements: elements}
+}
+
+func has(args ...Object) Object {
+	m, err := mapArgument("has", 2, args)
+	if err != nil {
+		return err
+	}
+
+	key, ok := args[1].(Hashable)
+	if !ok {
+		return NewEvalErrorObject("unusable as map key: %s", args[1].Type())
+	}
+
+	if _, ok := m.Get(key); ok {
+		return TRUE
+	}
+	return FALSE
+}
+
+// deleteKey removes a key from a map, deleting a key the map doesn't hold is no error
+func deleteKey(args ...Object) Object {
+	m, err := mapArgument("delete", 2, args)
+	if err != nil {
+		return err
+	}
+
+	key, ok := args[1].(Hashable)
+	if !ok {
+		return NewEvalErrorObject("unusable as map key: %s", args[1].Type())
+	}
+
+	m.Delete(key)
+
+	return NULL
+}
+
+// mapArgument checks the number of arguments of the native function name and whether the first is a map
+func mapArgument(name string, n int, args []Object) (*Map, Object) {
+	if len(args) != n {
+		return nil, NewEvalErrorObject("expected %d argument(s) for %s got=%d", n, name, len(args))
+	}
+
+	m, ok := args[0].(*Map)
+	if !ok {
+		return nil, NewEvalErrorObject("argument to %q must be MAP, got=%s", name, args[0].Type())
+	}
+
+	return m, nil
+}
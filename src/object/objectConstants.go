@@ -4,4 +4,9 @@ var (
 	NULL  = &Null{}
 	TRUE  = &Boolean{Value: true}
 	FALSE = &Boolean{Value: false}
+
+	BREAK    = &Break{}
+	CONTINUE = &Continue{}
+
+	PLACEHOLDER = &Placeholder{}
 )
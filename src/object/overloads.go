@@ -0,0 +1,139 @@
+package object
+
+import (
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/utility/slice"
+)
+
+// Overloads are the functions declared under one name in one scope, they differ in their number of parameters or the
+// declared types of their parameters. A call runs the function matching its arguments most specifically.
+type Overloads struct {
+	Name      string
+	Functions []*Function
+}
+
+func (o *Overloads) Type() ObjectType {
+	return FUNCTION_OBJ
+}
+
+func (o *Overloads) Inspect() string {
+	return strings.Join(slice.Map(o.Functions, func(fn *Function) string {
+		return fn.Inspect()
+	}), "\n")
+}
+
+// Add adds the function, it replaces the function with the same signature when there is one
+func (o *Overloads) Add(fn *Function) {
+	for i, existing := range o.Functions {
+		if SameParameters(existing.Parameters, fn.Parameters) {
+			o.Functions[i] = fn
+			return
+		}
+	}
+
+	o.Functions = append(o.Functions, fn)
+}
+
+// Signature formats the function as overload of the name, e.g. area(scale int)
+func (o *Overloads) Signature(fn *Function) string {
+	name := &ast.IdentifierLiteral{Value: o.Name}
+	return (&ast.Signature{Name: name, Parameters: fn.Parameters}).String()
+}
+
+// Signatures formats the functions as overloads of the name separated by commas
+func (o *Overloads) Signatures(functions []*Function) string {
+	return strings.Join(slice.Map(functions, o.Signature), ", ")
+}
+
+// SameParameters reports whether the parameters have the same signature, the same number of parameters with the same
+// declared types
+func SameParameters(a, b []*ast.Parameter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if (a[i].Type == nil) != (b[i].Type == nil) || a[i].Nullable != b[i].Nullable || a[i].Variadic != b[i].Variadic {
+			return false
+		}
+		if a[i].Optional != b[i].Optional || (a[i].Default == nil) != (b[i].Default == nil) {
+			return false
+		}
+		if a[i].Type != nil && a[i].Type.Value != b[i].Type.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Specificity ranks how specifically a parameter matches an argument, an overload is more specific than another when
+// it matches no argument less and at least one more specifically
+const (
+	MatchesAny           = iota // untyped parameters and unknown types
+	MatchesTypeParameter        // unconstrained type parameters, they still need the same type for all their values
+	MatchesInterface            // interfaces and constrained type parameters
+	MatchesExactly              // builtin types, struct types and null for nullable types
+)
+
+// builtinTypes map the names of the builtin types to the types of their values
+var builtinTypes = map[string]ObjectType{
+	"int":    INTEGER_OBJ,
+	"string": STRING_OBJ,
+	"bool":   BOOLEAN_OBJ,
+	"array":  ARRAY_OBJ,
+	"map":    MAP_OBJ,
+}
+
+// BuiltinType returns the type of the values of the builtin type with the name, e.g. INTEGER for int
+func BuiltinType(name string) (ObjectType, bool) {
+	objectType, ok := builtinTypes[name]
+	return objectType, ok
+}
+
+// MatchesArity ranks how a function takes the number of arguments like a parameter matching one, it is more
+// specific when every argument has a parameter of its own and no optional or variadic parameter is left without one
+func MatchesArity(parameters []*ast.Parameter, count int) int {
+	if _, most := ast.Arity(parameters); most == count {
+		return MatchesExactly
+	}
+
+	return MatchesAny
+}
+
+// MostSpecific returns the indexes of the candidates no other candidate is more specific than, the candidates are the
+// specificities of the arguments for each matching overload
+func MostSpecific(candidates [][]int) []int {
+	var result []int
+
+	for i, candidate := range candidates {
+		dominated := false
+		for j, other := range candidates {
+			if i != j && moreSpecific(other, candidate) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+func moreSpecific(a, b []int) bool {
+	more := false
+
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		more = more || a[i] > b[i]
+	}
+
+	return more
+}
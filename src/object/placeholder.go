@@ -0,0 +1,15 @@
+package object
+
+const PLACEHOLDER_OBJ = "PLACEHOLDER"
+
+// Placeholder is the value of the blank identifier passed as argument, it leaves the argument out of a partial
+// application. It is an object of its own so no evaluated argument is mistaken for it.
+type Placeholder struct{}
+
+func (p *Placeholder) Type() ObjectType {
+	return PLACEHOLDER_OBJ
+}
+
+func (p *Placeholder) Inspect() string {
+	return "_"
+}
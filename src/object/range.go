@@ -0,0 +1,79 @@
+package object
+
+import (
+	"fmt"
+	"math"
+)
+
+const RANGE_OBJ = "RANGE"
+
+// Range is a sequence of integers from Start to End with Step between them, End is left out when Exclusive. Its
+// elements are computed when they are asked for instead of being stored.
+type Range struct {
+	Start, End, Step int64
+	Exclusive        bool
+}
+
+func (r *Range) Type() ObjectType {
+	return RANGE_OBJ
+}
+
+func (r *Range) Inspect() string {
+	operator := ".."
+	if r.Exclusive {
+		operator = "..<"
+	}
+
+	if r.Step == r.defaultStep() {
+		return fmt.Sprintf("%d%s%d", r.Start, operator, r.End)
+	}
+	return fmt.Sprintf("%d%s%d step %d", r.Start, operator, r.End, r.Step)
+}
+
+// defaultStep is the step of a range without one, it counts down when the end is below the start
+func (r *Range) defaultStep() int64 {
+	if r.End < r.Start {
+		return -1
+	}
+	return 1
+}
+
+// Len returns the number of integers in the range, it is empty when the step points away from the end. The count is
+// computed unsigned as the distance of far apart bounds exceeds an integer, ok is false when the count does as well.
+func (r *Range) Len() (length int64, ok bool) {
+	last := r.End
+	switch {
+	case r.Exclusive && r.Step > 0:
+		if last == math.MinInt64 {
+			return 0, true
+		}
+		last--
+	case r.Exclusive:
+		if last == math.MaxInt64 {
+			return 0, true
+		}
+		last++
+	}
+
+	var distance, step uint64
+	switch {
+	case r.Step > 0 && last >= r.Start:
+		distance, step = uint64(last)-uint64(r.Start), uint64(r.Step)
+	case r.Step < 0 && last <= r.Start:
+		distance, step = uint64(r.Start)-uint64(last), uint64(-(r.Step+1))+1
+	default:
+		return 0, true
+	}
+
+	if distance/step >= math.MaxInt64 {
+		return math.MaxInt64, false
+	}
+
+	return int64(distance/step) + 1, true
+}
+
+// At returns the integer at index i of the range, the index has to be less than its length. The product of the index
+// and the step may wrap around, adding it to the start wraps back to the integer in the range.
+func (r *Range) At(i int64) *Integer {
+	return &Integer{Value: r.Start + i*r.Step}
+}
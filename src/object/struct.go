@@ -0,0 +1,140 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/utility/observer"
+)
+
+const (
+	STRUCT_TYPE_OBJ = "STRUCT_TYPE"
+	STRUCT_OBJ      = "STRUCT"
+)
+
+// StructType is the value of a struct type declaration, the methods are bound to a struct when accessed
+type StructType struct {
+	Name           string
+	TypeParameters []*TypeParameter
+	Fields         []string
+	FieldTypes     map[string]FieldType // declared types of the fields declaring one
+	Methods        map[string]*Function
+	Invariants     []*ast.Clause
+	Env            *Environment // the environment of the declaration, invariants are evaluated in it
+}
+
+// FieldType is the declared type of a field, only a nullable type accepts null
+type FieldType struct {
+	Name     string
+	Nullable bool
+}
+
+func (st *StructType) Type() ObjectType {
+	return STRUCT_TYPE_OBJ
+}
+
+func (st *StructType) Inspect() string {
+	return "struct " + st.Name
+}
+
+func (st *StructType) HasField(name string) bool {
+	for _, field := range st.Fields {
+		if field == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FieldChange is passed to the observers of a struct when a field is assigned
+type FieldChange struct {
+	Name  string
+	Value Object
+}
+
+// Struct is mutable, all references share it and observers are notified of every field assignment
+type Struct struct {
+	StructType    *StructType
+	fields        map[string]Object
+	typeArguments *TypeArguments
+	observer.BaseObservable[*FieldChange]
+}
+
+// NewStruct creates a struct of the given type with all fields null
+func NewStruct(structType *StructType) *Struct {
+	s := &Struct{
+		StructType:    structType,
+		fields:        make(map[string]Object),
+		typeArguments: NewTypeArguments(structType.TypeParameters),
+	}
+	for _, field := range structType.Fields {
+		s.fields[field] = NULL
+	}
+
+	return s
+}
+
+func (s *Struct) Type() ObjectType {
+	return STRUCT_OBJ
+}
+
+func (s *Struct) Inspect() string {
+	var out bytes.Buffer
+
+	var fields []string
+	for _, field := range s.StructType.Fields {
+		fields = append(fields, field+": "+s.fields[field].Inspect())
+	}
+
+	out.WriteString(s.StructType.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (s *Struct) Get(name string) (Object, bool) {
+	value, ok := s.fields[name]
+	return value, ok
+}
+
+// CheckField checks the value against the declared type of the field, only a nullable type accepts null. The type
+// arguments of a generic struct are inferred from the first values assigned to the fields typed by a type parameter.
+func (s *Struct) CheckField(name string, value Object) error {
+	fieldType, ok := s.StructType.FieldTypes[name]
+	if !ok {
+		return nil
+	}
+
+	if value == NULL {
+		if fieldType.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s is not nullable", fieldType.Name)
+	}
+
+	if builtin, ok := BuiltinType(fieldType.Name); ok && !s.typeArguments.IsParameter(fieldType.Name) {
+		if value.Type() != builtin {
+			return fmt.Errorf("%s is not of type %s", TypeName(value), fieldType.Name)
+		}
+		return nil
+	}
+
+	return s.typeArguments.Bind(fieldType.Name, value)
+}
+
+// Set assigns a declared field and notifies the observers, it reports whether the field is declared
+func (s *Struct) Set(name string, value Object) bool {
+	if _, ok := s.fields[name]; !ok {
+		return false
+	}
+
+	s.fields[name] = value
+	s.NotifyAll(&FieldChange{Name: name, Value: value})
+
+	return true
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"strings"
+
+	"Flow/src/ast"
+)
+
+// dedentStringParts strips the common indentation of a multi-line raw string, a blank first line and a whitespace
+// only last line are dropped so the delimiters can be placed on their own lines, templates count as line content. The
+// common indentation is the longest run of spaces and tabs all non blank lines start with, so a tab and a space are
+// never taken for each other and mixed indentation only strips the characters the lines agree on.
+func dedentStringParts(parts []ast.StringLiteralPart) []ast.StringLiteralPart {
+	texts, templates := splitStringParts(parts)
+
+	if !containsNewline(texts) {
+		return parts
+	}
+
+	if idx := strings.IndexByte(texts[0], '\n'); idx >= 0 && isBlank(texts[0][:idx]) {
+		texts[0] = texts[0][idx+1:]
+	}
+
+	last := len(texts) - 1
+	if idx := strings.LastIndexByte(texts[last], '\n'); idx >= 0 && isBlank(texts[last][idx+1:]) {
+		texts[last] = texts[last][:idx]
+	}
+
+	var indent string
+	found := false
+	forEachLine(texts, func(i, start int) {
+		width := indentWidth(texts[i][start:])
+		rest := texts[i][start+width:]
+
+		blank := strings.HasPrefix(rest, "\n") || (rest == "" && i == last)
+		if blank {
+			return
+		}
+
+		if !found {
+			indent, found = texts[i][start:start+width], true
+		} else {
+			indent = commonPrefix(indent, texts[i][start:start+width])
+		}
+	})
+
+	if indent != "" {
+		for i, text := range texts {
+			texts[i] = dedentText(text, indent, i == 0)
+		}
+	}
+
+	return joinStringParts(texts, templates)
+}
+
+// splitStringParts splits the parts into the text around the templates, text i precedes template i
+func splitStringParts(parts []ast.StringLiteralPart) ([]string, []ast.StringLiteralPart) {
+	texts := []string{""}
+	var templates []ast.StringLiteralPart
+
+	for _, part := range parts {
+		if part.Expr != nil {
+			templates = append(templates, ast.StringLiteralPart{Expr: part.Expr, Interpolation: part.Interpolation})
+			texts = append(texts, "")
+		}
+		if part.CharacterString != nil {
+			texts[len(texts)-1] += *part.CharacterString
+		}
+	}
+
+	return texts, templates
+}
+
+func joinStringParts(texts []string, templates []ast.StringLiteralPart) []ast.StringLiteralPart {
+	var parts []ast.StringLiteralPart
+
+	if texts[0] != "" {
+		text := texts[0]
+		parts = append(parts, ast.StringLiteralPart{CharacterString: &text})
+	}
+
+	for i, template := range templates {
+		if text := texts[i+1]; text != "" {
+			template.CharacterString = &text
+		}
+		parts = append(parts, template)
+	}
+
+	return parts
+}
+
+// forEachLine calls fn with the text index and offset of every line start, text following a template is no line start
+func forEachLine(texts []string, fn func(i, start int)) {
+	for i, text := range texts {
+		if i == 0 {
+			fn(i, 0)
+		}
+		for start := strings.IndexByte(text, '\n'); start >= 0; {
+			fn(i, start+1)
+
+			next := strings.IndexByte(text[start+1:], '\n')
+			if next < 0 {
+				break
+			}
+			start += next + 1
+		}
+	}
+}
+
+// dedentText removes the indent from every line start in text, a blank line shorter than the indent loses the part of
+// the indent it starts with
+func dedentText(text string, indent string, startsLine bool) string {
+	var out strings.Builder
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		if i > 0 || startsLine {
+			line = line[len(commonPrefix(line[:indentWidth(line)], indent)):]
+		}
+		out.WriteString(line)
+	}
+
+	return out.String()
+}
+
+func indentWidth(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+func isBlank(s string) bool {
+	return strings.TrimLeft(s, " \t") == ""
+}
+
+func containsNewline(texts []string) bool {
+	for _, text := range texts {
+		if strings.Contains(text, "\n") {
+			return true
+		}
+	}
+	return false
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
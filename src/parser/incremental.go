@@ -0,0 +1,338 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/error"
+	"Flow/src/lexer"
+	"Flow/src/token"
+)
+
+// Document holds the tokens and ast of a source split into units, a unit is the result of a single ParseProgram
+// iteration so mostly a top level statement. An edit only relexes and reparses the units affected by it, the units
+// following the edit are reused with shifted positions as soon as the reparse lines up with them again. The result is
+// identical to a full parse of the edited source.
+type Document struct {
+	source string
+	units  []unit
+}
+
+// unit is the result of a single ParseProgram iteration, the units tile the source
+type unit struct {
+	start, end int // start is the end of the previous unit, end is the end of the last token of the unit
+	read       int // end of the furthest token lexed while parsing the unit, the unit depends on the source up to here
+	clean      bool
+	statement  ast.Statement
+	tokens     []*token.Token
+	errors     []cerr.ParseError
+}
+
+func NewDocument(source string) *Document {
+	d := &Document{source: source}
+	d.units = d.parse(0, nil)
+	return d
+}
+
+func (d *Document) Source() string {
+	return d.source
+}
+
+// Program returns the program as ParseProgram would return it for the current source
+func (d *Document) Program() *ast.Program {
+	program := &ast.Program{Statements: []ast.Statement{}}
+
+	for _, u := range d.units {
+		if u.statement != nil {
+			program.Statements = append(program.Statements, u.statement)
+		}
+	}
+
+	return program
+}
+
+func (d *Document) Errors() []cerr.ParseError {
+	var errors []cerr.ParseError
+
+	for _, u := range d.units {
+		errors = append(errors, u.errors...)
+	}
+
+	return errors
+}
+
+// Tokens returns the tokens of the current source without the closing EOF token
+func (d *Document) Tokens() []*token.Token {
+	var tokens []*token.Token
+
+	for _, u := range d.units {
+		tokens = append(tokens, u.tokens...)
+	}
+
+	return tokens
+}
+
+// Edit replaces the source between byte offsets start and end with text, statements and tokens of units following the
+// edit are reused so their positions are shifted in place
+func (d *Document) Edit(start, end int, text string) error {
+	if start < 0 || end < start || end > len(d.source) {
+		return fmt.Errorf("invalid edit range %d:%d for source of length %d", start, end, len(d.source))
+	}
+
+	old := d.source
+	d.source = old[:start] + text + old[end:]
+
+	first := d.firstAffectedUnit(start)
+	if first == -1 {
+		d.units = d.parse(0, nil)
+		return nil
+	}
+
+	delta := len(text) - (end - start)
+
+	// units starting after the edit in a clean lexer state are unaffected, the reparse can stop at their start
+	resyncAt := make(map[int]int)
+	for i := first + 1; i < len(d.units); i++ {
+		if d.units[i].start >= end && d.units[i].clean {
+			resyncAt[d.units[i].start+delta] = i
+		}
+	}
+
+	resynced := -1
+	units := d.parse(d.units[first].start, func(offset int) bool {
+		if i, ok := resyncAt[offset]; ok {
+			resynced = i
+			return true
+		}
+		return false
+	})
+
+	units = append(d.units[:first:first], units...)
+
+	if resynced != -1 {
+		shift := newPositionShift(old, d.source, end, delta)
+		shift.shifted = make(map[*token.Token]bool)
+		for _, u := range d.units[resynced:] {
+			units = append(units, shift.unit(u))
+		}
+	}
+
+	d.units = units
+	return nil
+}
+
+// firstAffectedUnit returns the first unit which lexed up to the edit, an edit touching the end of a token can change
+// the token so the bound is inclusive. Units starting inside a string depend on preceding units so those are included.
+func (d *Document) firstAffectedUnit(start int) int {
+	for i, u := range d.units {
+		if u.read >= start {
+			for i > 0 && !d.units[i].clean {
+				i--
+			}
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parse parses units from offset until EOF or until resync accepts the offset of a unit boundary
+func (d *Document) parse(offset int, resync func(offset int) bool) []unit {
+	l := newTrackingLexer(d.source, offset)
+	p := New(l).(*parser)
+
+	var units []unit
+	for p.curToken.Type != token.EOF {
+		first := len(l.consumed) - 2 // parser holds the current and peek token
+		errorCount := len(p.errors)
+
+		stmt := p.parseStatement()
+
+		last := len(l.consumed) - 2
+		u := unit{
+			start:     offset,
+			end:       l.consumed[last].end,
+			clean:     l.consumed[first].clean,
+			statement: stmt,
+			errors:    append([]cerr.ParseError(nil), p.errors[errorCount:]...),
+		}
+		for _, lexed := range l.consumed[first : last+1] {
+			if lexed.tok.Type != token.EOF {
+				u.tokens = append(u.tokens, lexed.tok)
+			}
+		}
+
+		p.nextToken()
+
+		u.read = l.read
+		units = append(units, u)
+		offset = u.end
+
+		if resync != nil && l.consumed[last+1].clean && resync(offset) {
+			break
+		}
+	}
+
+	return units
+}
+
+// trackingLexer buffers the tokens of the lexer to know the source offsets of the tokens and how far the parser looked
+type trackingLexer struct {
+	l        offsetLexer
+	source   string
+	offset   int
+	buffered []lexedToken
+	consumed []lexedToken
+	read     int
+}
+
+type offsetLexer interface {
+	NextToken() *token.Token
+	Offset() int
+	InString() bool
+}
+
+type lexedToken struct {
+	tok   *token.Token
+	end   int
+	clean bool // lexer was outside string literals before lexing the token
+}
+
+func newTrackingLexer(source string, offset int) *trackingLexer {
+	return &trackingLexer{l: lexer.NewAt(source, offset), source: source, offset: offset, read: offset}
+}
+
+func (t *trackingLexer) NextToken() *token.Token {
+	if len(t.buffered) == 0 {
+		t.lex()
+	}
+
+	next := t.buffered[0]
+	t.buffered = t.buffered[1:]
+	t.consumed = append(t.consumed, next)
+	t.offset = next.end
+
+	return next.tok
+}
+
+// Offset returns the end of the last token returned by NextToken, the lexer itself is ahead by the buffered tokens
+func (t *trackingLexer) Offset() int {
+	return t.offset
+}
+
+func (t *trackingLexer) Source() string {
+	return t.source
+}
+
+// PeekN mirrors the lexer which requires n characters left to peek n tokens
+func (t *trackingLexer) PeekN(n int) (bool, *token.Token) {
+	if n < 1 || t.offset+n > len(t.source) {
+		return false, nil
+	}
+
+	for len(t.buffered) < n {
+		t.lex()
+	}
+
+	return true, t.buffered[n-1].tok
+}
+
+func (t *trackingLexer) lex() {
+	clean := !t.l.InString()
+	tok := t.l.NextToken()
+	end := t.l.Offset()
+
+	if end > t.read {
+		t.read = end
+	}
+
+	t.buffered = append(t.buffered, lexedToken{tok, end, clean})
+}
+
+// positionShift moves units from behind the edit in the old source to their place in the new source
+type positionShift struct {
+	offset, line int
+	endLine, col int                   // tokens on the line of the edit end also shift columns
+	shifted      map[*token.Token]bool // errors can point to tokens of other units, each token is shifted once
+}
+
+func newPositionShift(old, updated string, end, delta int) positionShift {
+	oldLine, oldCol := position(old, end)
+	newLine, newCol := position(updated, end+delta)
+
+	return positionShift{offset: delta, line: newLine - oldLine, endLine: oldLine, col: newCol - oldCol}
+}
+
+// position returns the line and relative position of offset the way the iterator counts them
+func position(source string, offset int) (line, col int) {
+	line = 1 + strings.Count(source[:offset], "\n")
+	lineStart := strings.LastIndexByte(source[:offset], '\n') + 1
+	col = 1 + offset - lineStart - strings.Count(source[lineStart:offset], "\r")
+	return line, col
+}
+
+func (s positionShift) unit(u unit) unit {
+	u.start += s.offset
+	u.end += s.offset
+	u.read += s.offset
+
+	for _, tok := range u.tokens {
+		s.token(tok)
+	}
+
+	for _, err := range u.errors {
+		s.token(err.Token())
+	}
+
+	if u.statement != nil {
+		s.node(reflect.ValueOf(u.statement))
+	}
+
+	return u
+}
+
+func (s positionShift) token(tok *token.Token) {
+	if s.shifted[tok] || tok.Line < 1 { // EOF has no position
+		return
+	}
+	s.shifted[tok] = true
+
+	if tok.Line == s.endLine {
+		tok.Pos += s.col
+	}
+	tok.Line += s.line
+}
+
+var tokenType = reflect.TypeOf(token.Token{})
+
+// node shifts all tokens in the ast node, the linked list of string literals is followed through its methods as its
+// links are unexported
+func (s positionShift) node(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			s.node(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			s.node(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == tokenType {
+			s.token(v.Addr().Interface().(*token.Token))
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				s.node(v.Field(i))
+			}
+		}
+
+		if hasNext := v.Addr().MethodByName("HasNext"); hasNext.IsValid() && hasNext.Call(nil)[0].Bool() {
+			s.node(v.Addr().MethodByName("Next").Call(nil)[0])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"math/rand"
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/lexer"
+	"Flow/src/token"
+)
+
+func (test *Suite) TestDocumentEdit() {
+	d := NewDocument("let a = 1;\nlet b = (x) => x + a;\nb(2);\n")
+	reused := d.Program().Statements[2]
+
+	test.NoError(d.Edit(8, 9, "10 * 3"))
+	test.Equal("let a = 10 * 3;\nlet b = (x) => x + a;\nb(2);\n", d.Source())
+	test.assertFullParse(d)
+
+	program := d.Program()
+	test.Equal("let a = (10 * 3);", program.Statements[0].String())
+	test.Same(reused, program.Statements[2], "statement after the edit should be reused")
+	test.Equal(3, program.Statements[2].(*ast.ExpressionStatement).Token.Line)
+}
+
+func (test *Suite) TestDocumentEdit_ShiftsPositions() {
+	d := NewDocument("let a = 1; let b = a;\nb;")
+
+	test.NoError(d.Edit(0, 0, "let z = 0;\n\n"))
+	test.assertFullParse(d)
+
+	test.NoError(d.Edit(19, 20, "100"))
+	test.assertFullParse(d)
+
+	test.NoError(d.Edit(0, 12, ""))
+	test.assertFullParse(d)
+}
+
+func (test *Suite) TestDocumentEdit_Strings() {
+	d := NewDocument("let a = \"x ${1}\";\nlet b = 2;\nlet c = \"\"\"\n  raw\n  \"\"\";\n")
+
+	edits := []struct {
+		start, end int
+		text       string
+	}{
+		{8, 9, ""},   // open string swallows the rest of the source
+		{8, 8, "\""}, // and closes it again
+		{12, 13, "${a}"},
+		{30, 30, "\"\"\""},
+		{0, 0, "\"\"\""},
+	}
+
+	for _, e := range edits {
+		test.NoError(d.Edit(e.start, e.end, e.text))
+		test.assertFullParse(d)
+	}
+}
+
+func (test *Suite) TestDocumentEdit_InvalidRange() {
+	d := NewDocument("let a = 1;")
+
+	test.EqualError(d.Edit(5, 4, ""), "invalid edit range 5:4 for source of length 10")
+	test.EqualError(d.Edit(0, 11, ""), "invalid edit range 0:11 for source of length 10")
+}
+
+func (test *Suite) TestDocumentEdit_Randomized() {
+	fragments := []string{
+		"let ", "a", "b", "snake_case", " = ", "1", "42", ";", "\n", " ", "(", ")", "x", ", ", " => ", "{", "}", "[", "]",
+		":", " + ", "*", " ? ", "if ", " else ", "return ", "\"", "\"\"\"", "${", "<", ">", "\\", "_", "true", "==", "!",
+	}
+	base := "let a = 1;\nlet add = (x, y) => x + y;\nlet s = \"v ${a} <a>\";\nlet arr = [1, 2, 3][1:2];\n" +
+		"if (a > 1) { add(a, 2) } else { a };\nlet raw = \"\"\"\n  ${a}\n  \"\"\";\nadd(a, arr[0]);\n"
+
+	random := rand.New(rand.NewSource(31))
+
+	for run := 0; run < 50; run++ {
+		d := NewDocument(base)
+
+		for i := 0; i < 40; i++ {
+			source := d.Source()
+			start := random.Intn(len(source) + 1)
+			end := start + random.Intn(min(len(source)-start, 6)+1)
+
+			var text strings.Builder
+			for n := random.Intn(3); n > 0; n-- {
+				text.WriteString(fragments[random.Intn(len(fragments))])
+			}
+
+			test.NoError(d.Edit(start, end, text.String()))
+			if !test.assertFullParse(d) {
+				test.T().Fatalf("run %d edit %d: replacing %q with %q in\n%s", run, i, source[start:end], text.String(), source)
+			}
+		}
+	}
+}
+
+// assertFullParse compares the document with a full lex and parse of its source
+func (test *Suite) assertFullParse(d *Document) bool {
+	p := New(lexer.New(d.Source()))
+	program := p.ParseProgram()
+
+	var errors, documentErrors []string
+	for _, err := range p.Errors() {
+		errors = append(errors, err.Error())
+	}
+	for _, err := range d.Errors() {
+		documentErrors = append(documentErrors, err.Error())
+	}
+
+	var tokens []*token.Token
+	l := lexer.New(d.Source())
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		tokens = append(tokens, tok)
+	}
+
+	return test.Equal(program, d.Program(), d.Source()) &&
+		test.Equal(errors, documentErrors, d.Source()) &&
+		test.Equal(tokens, d.Tokens(), d.Source())
+}
@@ -1,12 +1,14 @@
 package parser
 
 import (
+	"fmt"
 	"strconv"
 
 	"Flow/src/ast"
 	"Flow/src/error"
 	"Flow/src/token"
 	"Flow/src/utility/convert"
+	"Flow/src/utility/escape"
 	"Flow/src/utility/linkedList"
 )
 
@@ -25,6 +27,20 @@ func (p *parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *parser) parseIdentifier() ast.Expression {
+	if p.peekToken.Type == token.ARROW && !p.noSingleParameterFunction {
+		return p.parseSingleParameterFunction()
+	}
+
+	return &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+}
+
+// parseBlankIdentifier parses _ which can only be assigned to, reading it is an error
+func (p *parser) parseBlankIdentifier() ast.Expression {
+	if p.peekToken.Type != token.ASSIGN {
+		p.registerError(cerr.Wrap(cerr.BlankIdentifierError(p.curToken), "parseBlankIdentifier"))
+		return nil
+	}
+
 	return &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
 }
 
@@ -35,36 +51,62 @@ func (p *parser) parseBooleanLiteral() ast.Expression {
 	}
 }
 
-func (p *parser) parseLParenExpression() ast.Expression {
-	var (
-		arrowFunction prefixParseFn = p.parseFunctionLiteralExpression
-		groupedExpr   prefixParseFn = p.parseGroupedExpression
-	)
+func (p *parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: *p.curToken}
+}
 
-	templates := []template{
-		{
-			match: arrowFnRegexp,
-			fn:    arrowFunction,
-			limit: nil,
-		},
-		{
-			match: groupedExprRegexp,
-			fn:    groupedExpr,
-			limit: nil,
-		},
+func (p *parser) parseLParenExpression() ast.Expression {
+	if p.isArrowFunction() {
+		return p.parseFunctionLiteralExpression()
 	}
 
-	parseFn := p.parseFnTemplateMatch(templates)
+	return p.parseGroupedExpression()
+}
 
-	if fn, ok := parseFn.(prefixParseFn); ok {
-		return fn()
+// isArrowFunction peeks past the parenthesis matching the current token, the parenthesis is the parameter list of an
+// arrow function when it is followed by the arrow symbol or a contract clause, optionally preceded by a return type
+func (p *parser) isArrowFunction() bool {
+	depth := 1
+
+	for i := 1; ; i++ {
+		ok, tok := p.peekTokenN(i)
+		if !ok || tok.Type == token.EOF {
+			return false
+		}
+
+		switch tok.Type {
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+		}
+
+		if depth == 0 {
+			return p.followsParameterList(i + 1)
+		}
 	}
+}
 
-	return nil
+// followsParameterList reports whether the token n ahead and those following it can follow the parameter list of an
+// arrow function, e.g. => or string? =>
+func (p *parser) followsParameterList(n int) bool {
+	ok, next := p.peekTokenN(n)
+	if ok && next.Type == token.IDENT {
+		n++
+		if ok, next = p.peekTokenN(n); ok && next.Type == token.QUESTION {
+			n++
+		}
+		ok, next = p.peekTokenN(n)
+	}
+
+	return ok && (next.Type == token.ARROW || next.Type == token.REQUIRE || next.Type == token.ENSURE)
 }
 
 // todo add error logging
 func (p *parser) parseGroupedExpression() ast.Expression {
+	defer p.allowStructLiterals(true)()
+	defer p.allowSingleParameterFunctions(true)()
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
@@ -104,13 +146,42 @@ func (p *parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseRangeExpression parses the upper bound of a range and its optional step, step is only a keyword following the
+// upper bound so it remains usable as a name, e.g. 0..n step 2
+func (p *parser) parseRangeExpression(left ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     *p.curToken,
+		Lower:     left,
+		Exclusive: p.curToken.Type == token.RANGE_EXCLUSIVE,
+	}
+
+	p.nextToken()
+	expression.Upper = p.parseExpression(RANGE)
+	if expression.Upper == nil {
+		return nil
+	}
+
+	if p.peekToken.Type == token.IDENT && p.peekToken.Literal == "step" {
+		p.nextToken()
+		p.nextToken()
+		expression.Step = p.parseExpression(RANGE)
+		if expression.Step == nil {
+			return nil
+		}
+	}
+
+	return expression
+}
+
 func (p *parser) parseIfExpression() ast.Expression {
 	expression := &ast.IfExpression{
 		Token: *p.curToken,
 	}
 
 	p.nextToken()
+	restore := p.allowStructLiterals(false)
 	expression.Condition = p.parseExpression(LOWEST)
+	restore()
 
 	if p.peekToken.Type != token.LBRACE {
 		err := cerr.UnexpectedCharError(p.peekToken, "{")
@@ -122,10 +193,21 @@ func (p *parser) parseIfExpression() ast.Expression {
 
 	expression.Consequence = p.parseBlockStatement()
 
-	if p.peekToken.Type == token.ELSE {
+	switch p.peekToken.Type {
+	case token.ELIF:
+		p.nextToken()
+		return p.parseElseIf(expression)
+	case token.ELSE:
 		p.nextToken()
 
+		if p.peekToken.Type == token.IF {
+			p.nextToken()
+			return p.parseElseIf(expression)
+		}
+
 		if p.peekToken.Type != token.LBRACE {
+			err := cerr.UnexpectedCharError(p.peekToken, "{")
+			p.registerError(cerr.Wrap(err, "parseIfExpression", "following else"))
 			return nil
 		}
 
@@ -136,6 +218,123 @@ func (p *parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+// parseMisplacedElif registers an error for elif not following an if expression
+func (p *parser) parseMisplacedElif() ast.Expression {
+	err := cerr.UnexpectedTokenError(p.curToken, token.IF)
+	p.registerError(cerr.Wrap(err, "parseMisplacedElif", "elif must follow an if expression"))
+	return nil
+}
+
+// parseElseIf parses the if expression following else or elif, it becomes the only statement of the alternative so
+// a chain is a nest of if expressions
+func (p *parser) parseElseIf(expression *ast.IfExpression) ast.Expression {
+	tok := *p.curToken
+
+	elseIf, ok := p.parseIfExpression().(*ast.IfExpression)
+	if !ok {
+		return nil
+	}
+
+	expression.Alternative = &ast.BlockStatement{
+		Token:      tok,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: elseIf}},
+	}
+
+	return expression
+}
+
+func (p *parser) parseSwitchExpression() ast.Expression {
+	expression := &ast.SwitchExpression{Token: *p.curToken}
+
+	if p.peekToken.Type != token.LBRACE {
+		p.nextToken()
+		restore := p.allowStructLiterals(false)
+		expression.Subject = p.parseExpression(LOWEST)
+		restore()
+	}
+
+	if p.peekToken.Type != token.LBRACE {
+		err := cerr.UnexpectedCharError(p.peekToken, "{")
+		p.registerError(cerr.Wrap(err, "parseSwitchExpression", "following switch subject"))
+		return nil
+	}
+
+	p.nextTokenN(2)
+
+	hasDefault := false
+	for p.curToken.Type != token.RBRACE {
+		switch p.curToken.Type {
+		case token.NEWLINE, token.SEMICOLON:
+			p.nextToken()
+			continue
+		case token.CASE, token.DEFAULT:
+		default:
+			err := cerr.UnexpectedTokenError(p.curToken, token.CASE)
+			p.registerError(cerr.Wrap(err, "parseSwitchExpression"))
+			return nil
+		}
+
+		switchCase := p.parseSwitchCase()
+		if switchCase == nil {
+			return nil
+		}
+
+		if switchCase.IsDefault() {
+			if hasDefault {
+				err := cerr.DuplicateDefaultCaseError(&switchCase.Token)
+				p.registerError(cerr.Wrap(err, "parseSwitchExpression"))
+				return nil
+			}
+			hasDefault = true
+		}
+
+		expression.Cases = append(expression.Cases, switchCase)
+	}
+
+	return expression
+}
+
+// parseSwitchCase parses a case or default clause, the body runs until the next clause or the end of the switch
+func (p *parser) parseSwitchCase() *ast.SwitchCase {
+	switchCase := &ast.SwitchCase{Token: *p.curToken}
+
+	if p.curToken.Type == token.CASE {
+		p.nextToken()
+		switchCase.Values = []ast.Expression{p.parseExpression(LOWEST)}
+
+		for p.peekToken.Type == token.COMMA {
+			p.nextTokenN(2)
+			switchCase.Values = append(switchCase.Values, p.parseExpression(LOWEST))
+		}
+	}
+
+	if p.peekToken.Type != token.COLON {
+		err := cerr.UnexpectedCharError(p.peekToken, token.COLON)
+		p.registerError(cerr.Wrap(err, "parseSwitchCase", "following case"))
+		return nil
+	}
+
+	p.nextToken()
+	switchCase.Body = &ast.BlockStatement{Token: *p.curToken, Statements: []ast.Statement{}}
+
+	p.nextToken()
+	for p.curToken.Type != token.CASE && p.curToken.Type != token.DEFAULT && p.curToken.Type != token.RBRACE {
+		if p.curToken.Type == token.EOF {
+			err := cerr.UnexpectedCharError(p.curToken, "}")
+			p.registerError(cerr.Wrap(err, "parseSwitchCase", "closing switch"))
+			return nil
+		}
+
+		stmt := p.parseStatement()
+		if stmt != nil {
+			switchCase.Body.Statements = append(switchCase.Body.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	return switchCase
+}
+
 func (p *parser) parseTernaryExpression(left ast.Expression) ast.Expression {
 	expression := &ast.TernaryExpression{
 		Token:     *p.curToken,
@@ -174,6 +373,15 @@ func (p *parser) parseFunctionLiteralExpression() ast.Expression {
 
 	p.nextToken()
 
+	if p.incrementOnMatch(token.IDENT) {
+		lit.ReturnType = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+		lit.ReturnNullable = p.incrementOnMatch(token.QUESTION)
+	}
+
+	if !p.parseFunctionContract(lit) {
+		return nil
+	}
+
 	if p.peekToken.Type != token.ARROW {
 		err := cerr.UnexpectedCharError(p.peekToken, token.ARROW)
 		p.registerError(cerr.Wrap(err, "parseFunctionLiteralExpression", "following function literal parameter list declaration"))
@@ -182,42 +390,177 @@ func (p *parser) parseFunctionLiteralExpression() ast.Expression {
 
 	p.nextToken()
 
-	if p.peekToken.Type != token.LBRACE {
+	return p.parseFunctionBody(lit)
+}
+
+// parseSingleParameterFunction parses an arrow function taking a single untyped parameter without parentheses, e.g.
+// x => x + 1
+func (p *parser) parseSingleParameterFunction() ast.Expression {
+	lit := &ast.FunctionLiteralExpression{
+		Token: *token.New(token.LPAREN, "(", p.curToken.Pos, p.curToken.Line),
+		Parameters: []*ast.Parameter{
+			{Name: &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}},
+		},
+	}
+
+	p.nextToken()
+
+	return p.parseFunctionBody(lit)
+}
+
+// parseFunctionBody parses the block or single expression following the arrow of a function literal
+func (p *parser) parseFunctionBody(lit *ast.FunctionLiteralExpression) ast.Expression {
+	p.skipNewlines()
+
+	// loops enclosing the function literal don't enclose its body
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	defer func() { p.loopDepth = loopDepth }()
+	defer p.allowStructLiterals(true)()
+	defer p.allowSingleParameterFunctions(true)()
+
+	if p.peekToken.Type == token.LBRACE {
+		p.nextToken()
+		lit.Body = p.parseBlockStatement()
+		return lit
+	}
+
+	if p.peekToken.Type == token.EOF {
 		err := cerr.UnexpectedCharError(p.peekToken, token.LBRACE)
-		p.registerError(cerr.Wrap(err, "parseFunctionLiteralExpression", "following function literal parameter list declaration"))
+		p.registerError(cerr.Wrap(err, "parseFunctionLiteralExpression", "following function literal arrow"))
 		return nil
 	}
 
 	p.nextToken()
 
-	lit.Body = p.parseBlockStatement()
+	lit.Body = p.parseExpressionBody()
 
 	return lit
 }
 
-func (p *parser) parseFunctionParameters() ([]*ast.IdentifierLiteral, cerr.ParseError) {
-	var identifiers []*ast.IdentifierLiteral
+// parseFunctionContract parses the require and ensure clauses following the parameter list, e.g.
+// (x int) require x > 0 ensure result > x => x * 2
+func (p *parser) parseFunctionContract(lit *ast.FunctionLiteralExpression) bool {
+	for p.peekToken.Type == token.REQUIRE || p.peekToken.Type == token.ENSURE {
+		p.nextToken()
 
-	if p.peekToken.Type == token.RPAREN {
-		return identifiers, nil
+		clause := p.parseClause()
+		if clause == nil {
+			return false
+		}
+
+		if clause.Token.Type == token.REQUIRE {
+			lit.Requires = append(lit.Requires, clause)
+		} else {
+			lit.Ensures = append(lit.Ensures, clause)
+		}
 	}
 
+	return true
+}
+
+// parseClause parses the condition following a require, ensure or invariant keyword
+func (p *parser) parseClause() *ast.Clause {
+	clause := &ast.Clause{Token: *p.curToken}
+	start := p.curEnd - len(p.curToken.Literal)
+
+	defer p.allowSingleParameterFunctions(false)()
+
 	p.nextToken()
 
-	ident := &ast.IdentifierLiteral{
-		Token: *p.curToken,
-		Value: p.curToken.Literal,
+	clause.Condition = p.parseExpression(LOWEST)
+	if clause.Condition == nil {
+		return nil
+	}
+	clause.Source = p.l.Source()[start:p.curEnd]
+
+	return clause
+}
+
+// parseExpressionBody parses the single expression body of an arrow function, e.g. (x) => x + 1
+// it is desugared into a block statement returning the expression
+func (p *parser) parseExpressionBody() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: *p.curToken}
+
+	stmt := &ast.ReturnStatement{
+		Token: *token.New(token.RETURN, "return", p.curToken.Pos, p.curToken.Line),
+	}
+	stmt.ReturnValue = p.parseExpression(LOWEST)
+
+	block.Statements = []ast.Statement{stmt}
+
+	return block
+}
+
+// parseGenericFunctionLiteral parses a function literal preceded by its type parameters, e.g. <T>(value T) => value
+func (p *parser) parseGenericFunctionLiteral() ast.Expression {
+	typeParameters, err := p.parseTypeParameters()
+	if err != nil {
+		p.registerError(cerr.Wrap(err, "parseGenericFunctionLiteral"))
+		return nil
+	}
+
+	p.nextToken()
+
+	function, ok := p.parseFunctionLiteralExpression().(*ast.FunctionLiteralExpression)
+	if !ok {
+		return nil
+	}
+	function.TypeParameters = typeParameters
+
+	return function
+}
+
+// parseTypeParameters parses the type parameters between angle brackets with their optional constraints, e.g.
+// <T, U Shape>. It starts at the opening and stops at the closing angle bracket.
+func (p *parser) parseTypeParameters() ([]*ast.TypeParameter, cerr.ParseError) {
+	var typeParameters []*ast.TypeParameter
+	names := make(map[string]bool)
+
+	for {
+		if !p.incrementOnMatch(token.IDENT) {
+			err := cerr.UnexpectedTokenError(p.peekToken, token.IDENT)
+			return nil, cerr.Wrap(err, "parseTypeParameters")
+		}
+
+		if names[p.curToken.Literal] {
+			return nil, cerr.Wrap(cerr.DuplicateTypeParameterError(p.curToken), "parseTypeParameters")
+		}
+		names[p.curToken.Literal] = true
+
+		typeParameter := &ast.TypeParameter{Name: &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}}
+		if p.incrementOnMatch(token.IDENT) {
+			typeParameter.Constraint = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+		}
+		typeParameters = append(typeParameters, typeParameter)
+
+		if !p.incrementOnMatch(token.COMMA) {
+			break
+		}
+	}
+
+	if !p.incrementOnMatch(token.GT) {
+		err := cerr.UnexpectedCharError(p.peekToken, token.GT)
+		return nil, cerr.Wrap(err, "parseTypeParameters")
+	}
+
+	return typeParameters, nil
+}
+
+func (p *parser) parseFunctionParameters() ([]*ast.Parameter, cerr.ParseError) {
+	var parameters []*ast.Parameter
+
+	if p.peekToken.Type == token.RPAREN {
+		return parameters, nil
 	}
-	identifiers = append(identifiers, ident)
+
+	p.nextToken()
+	parameters = append(parameters, p.parseFunctionParameter())
 
 	for p.peekToken.Type == token.COMMA {
 		p.nextToken()
 		p.nextToken()
-		ident := &ast.IdentifierLiteral{
-			Token: *p.curToken,
-			Value: p.curToken.Literal,
-		}
-		identifiers = append(identifiers, ident)
+		parameters = append(parameters, p.parseFunctionParameter())
 	}
 
 	if p.peekToken.Type != token.RPAREN {
@@ -225,7 +568,105 @@ func (p *parser) parseFunctionParameters() ([]*ast.IdentifierLiteral, cerr.Parse
 		return nil, cerr.Wrap(err, "parseFunctionParameters")
 	}
 
-	return identifiers, nil
+	if err := checkParameterOrder(parameters); err != nil {
+		return nil, cerr.Wrap(err, "parseFunctionParameters")
+	}
+
+	if err := checkDefaults(parameters); err != nil {
+		return nil, cerr.Wrap(err, "parseFunctionParameters")
+	}
+
+	return parameters, nil
+}
+
+// parseFunctionParameter parses a parameter name followed by its optional type, e.g. s Shape or s Shape?. The name is
+// followed by ? for an optional parameter, preceded by ... for a variadic one, and the parameter may end with a default
+// value, e.g. n int = 10.
+func (p *parser) parseFunctionParameter() *ast.Parameter {
+	parameter := &ast.Parameter{}
+
+	if p.curToken.Type == token.ELLIPSIS {
+		parameter.Variadic = true
+		p.nextToken()
+	}
+
+	parameter.Name = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+	parameter.Optional = p.incrementOnMatch(token.QUESTION)
+
+	if p.incrementOnMatch(token.IDENT) {
+		parameter.Type = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+		parameter.Nullable = p.incrementOnMatch(token.QUESTION)
+	}
+
+	if p.incrementOnMatch(token.ASSIGN) {
+		defer p.allowStructLiterals(true)()
+
+		p.nextToken()
+		parameter.Default = p.parseExpression(LOWEST)
+	}
+
+	return parameter
+}
+
+// checkParameterOrder checks that the required parameters come first and only the last parameter is variadic, an
+// optional parameter has no default value as it is null when its argument is missing
+func checkParameterOrder(parameters []*ast.Parameter) cerr.ParseError {
+	optional := false
+
+	for i, parameter := range parameters {
+		switch {
+		case parameter.Variadic && i != len(parameters)-1:
+			return cerr.InvalidParameterError(&parameter.Name.Token, "a variadic parameter has to be the last one")
+		case parameter.Variadic && (parameter.Optional || parameter.Default != nil):
+			return cerr.InvalidParameterError(&parameter.Name.Token, "a variadic parameter can't be optional")
+		case parameter.Optional && parameter.Default != nil:
+			return cerr.InvalidParameterError(&parameter.Name.Token, "an optional parameter can't have a default value")
+		case parameter.Optional || parameter.Default != nil:
+			optional = true
+		case optional && !parameter.Variadic:
+			return cerr.InvalidParameterError(&parameter.Name.Token, "a required parameter can't follow an optional one")
+		}
+	}
+
+	return nil
+}
+
+// checkDefaults checks that default values only refer to the parameters preceding theirs, a default value is evaluated
+// when its argument is missing and only those are bound then. Function literals in a default value are skipped as
+// their bodies are evaluated later.
+func checkDefaults(parameters []*ast.Parameter) cerr.ParseError {
+	for i, parameter := range parameters {
+		if parameter.Default == nil {
+			continue
+		}
+
+		following := make(map[string]bool)
+		for _, p := range parameters[i:] {
+			following[p.Name.Value] = true
+		}
+
+		var reference string
+		ast.Inspect(parameter.Default, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FunctionLiteralExpression:
+				return false
+			case *ast.IdentifierLiteral:
+				if reference == "" && following[node.Value] {
+					reference = node.Value
+				}
+			}
+			return reference == ""
+		})
+
+		if reference == parameter.Name.Value {
+			return cerr.InvalidParameterError(&parameter.Name.Token, "its default value refers to itself")
+		}
+		if reference != "" {
+			return cerr.InvalidParameterError(&parameter.Name.Token, fmt.Sprintf("its default value refers to parameter %s declared after it", reference))
+		}
+	}
+
+	return nil
 }
 
 func (p *parser) parseBlockStatement() *ast.BlockStatement {
@@ -246,44 +687,74 @@ func (p *parser) parseBlockStatement() *ast.BlockStatement {
 }
 
 func (p *parser) parseCallExpression(function ast.Expression) ast.Expression {
+	// the token is copied before parsing the arguments moves the current token past them
 	exp := &ast.CallExpression{
-		Token:     *p.curToken,
-		Function:  function,
-		Arguments: p.parseExpressionList(token.RPAREN),
+		Token:    *p.curToken,
+		Function: function,
 	}
+	exp.Arguments = p.parseList(token.RPAREN, p.parseCallArgument)
 
 	return exp
 }
 
+// parseCallArgument parses an argument of a call, the blank identifier is a placeholder for an argument left out of a
+// partial application, e.g. f(_, 2)
+func (p *parser) parseCallArgument() ast.Expression {
+	if p.curToken.Type == token.BLANK && (p.peekToken.Type == token.COMMA || p.peekToken.Type == token.RPAREN) {
+		return &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+	}
+
+	return p.parseExpression(LOWEST)
+}
+
 func (p *parser) parseStringLiteral() ast.Expression {
 	exp := ast.StringLiteral{Token: *p.curToken}
 
+	closing := p.curToken.Type
+	if closing == token.RAW_STRING_DELIMITER {
+		exp.Style = ast.RawString
+	}
+
 	p.nextToken()
 
-	l := linkedList.LinkedList[ast.StringLiteralPart]{}
-	for p.curToken.Literal != token.STRING_DELIMITER {
+	var parts []ast.StringLiteralPart
+	for p.curToken.Type != closing {
 		part := ast.StringLiteralPart{}
-		if p.curToken.Type == token.STRING_TEMPLATE_OPEN {
-			p.nextToken()
-			part.Expr = p.parseExpressionStatement()
-			p.nextToken()
-			p.nextToken()
+		if p.curToken.Type == token.STRING_TEMPLATE_OPEN || p.curToken.Type == token.STRING_INTERPOLATION_OPEN {
+			if !p.parseStringTemplate(&part) {
+				return nil
+			}
 		}
 
 		if p.curToken.Type == token.STRING_CHARACTERS {
-			part.CharacterString = &p.curToken.Literal
+			value := p.curToken.Literal
+			if exp.Style == ast.QuotedString {
+				var err error
+				if value, err = escape.Unescape(p.curToken.Literal); err != nil {
+					p.registerError(cerr.Wrap(cerr.InvalidEscapeSequenceError(p.curToken, err.Error()), "parseStringLiteral"))
+					return nil
+				}
+			}
+			part.CharacterString = &value
 			p.nextToken()
 		}
 
-		if p.curToken.Type == token.EOF {
-			panic("Reached end of file before closing string!")
+		if part.Expr == nil && part.CharacterString == nil {
+			err := cerr.UnexpectedCharError(p.curToken, string(closing))
+			p.registerError(cerr.Wrap(err, "parseStringLiteral", "closing string literal"))
+			return nil
 		}
 
-		l.Push(part)
+		parts = append(parts, part)
+	}
+
+	if exp.Style == ast.RawString {
+		parts = dedentStringParts(parts)
 	}
 
-	if p.curToken.Type != token.STRING_DELIMITER {
-		panic("Not closing string!")
+	l := linkedList.LinkedList[ast.StringLiteralPart]{}
+	for _, part := range parts {
+		l.Push(part)
 	}
 
 	if l.Value == nil { // empty string literal will be set to empty string value
@@ -294,14 +765,196 @@ func (p *parser) parseStringLiteral() ast.Expression {
 	return &exp
 }
 
+// parseStringTemplate parses the expression of a ${...} or <...> template inside a string literal
+func (p *parser) parseStringTemplate(part *ast.StringLiteralPart) bool {
+	var closing token.Type = token.RBRACE
+	if p.curToken.Type == token.STRING_INTERPOLATION_OPEN {
+		closing = token.STRING_INTERPOLATION_CLOSE
+	}
+
+	part.Interpolation = p.curToken.Type
+
+	p.nextToken()
+	part.Expr = p.parseExpressionStatement()
+
+	if p.peekToken.Type != closing {
+		err := cerr.UnexpectedTokenError(p.peekToken, closing)
+		p.registerError(cerr.Wrap(err, "parseStringTemplate", "closing string template"))
+		return false
+	}
+
+	p.nextTokenN(2)
+
+	return true
+}
+
+// parseArrayLiteral parses the elements of an array, a single element followed by for clauses is a comprehension
 func (p *parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: *p.curToken}
 
-	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Elements = p.parseList(token.RBRACKET, func() ast.Expression {
+		element := p.parseExpression(LOWEST)
+		if p.peekToken.Type == token.FOR && !array.Generator {
+			array.Generator = true
+			array.Clauses = p.parseComprehensionClauses()
+		}
+		return element
+	})
+
+	if array.Generator && array.Clauses == nil {
+		return nil
+	}
+
+	if array.Generator && len(array.Elements) != 1 {
+		err := cerr.UnexpectedCharError(&array.Clauses[0].Token, token.RBRACKET)
+		p.registerError(cerr.Wrap(err, "parseArrayLiteral", "comprehension of more than one element"))
+		return nil
+	}
 
 	return array
 }
 
+// parseComprehensionClauses parses the for and if clauses following the element of a comprehension, the first clause
+// is a for clause and the last one is followed by the closing bracket. It returns nil when the clauses are invalid.
+func (p *parser) parseComprehensionClauses() []*ast.ComprehensionClause {
+	var clauses []*ast.ComprehensionClause
+
+	for p.peekToken.Type == token.FOR || p.peekToken.Type == token.IF && clauses != nil {
+		p.nextToken()
+		clause := &ast.ComprehensionClause{Token: *p.curToken}
+
+		if clause.Token.Type == token.IF {
+			p.nextToken()
+			clause.Condition = p.parseExpression(LOWEST)
+		} else {
+			var ok bool
+			if clause.Value, clause.Index, clause.Iterable, ok = p.parseForInHeader(); !ok {
+				return nil
+			}
+		}
+
+		clauses = append(clauses, clause)
+	}
+
+	if p.peekToken.Type != token.RBRACKET {
+		err := cerr.UnexpectedCharError(p.peekToken, token.RBRACKET)
+		p.registerError(cerr.Wrap(err, "parseArrayLiteral", "closing comprehension"))
+		return nil
+	}
+
+	return clauses
+}
+
+// parseMapLiteral parses a map literal, a brace only opens a map in the position of an expression. The braces
+// following if, else, switch and for headers and the arrow of a function literal always open a block.
+func (p *parser) parseMapLiteral() ast.Expression {
+	defer p.allowStructLiterals(true)()
+
+	lit := &ast.MapLiteral{Token: *p.curToken}
+
+	p.skipNewlines()
+
+	for p.peekToken.Type != token.RBRACE {
+		p.nextToken()
+		pair := &ast.MapLiteralPair{Key: p.parseExpression(LOWEST)}
+
+		if p.peekToken.Type != token.COLON {
+			err := cerr.UnexpectedCharError(p.peekToken, token.COLON)
+			p.registerError(cerr.Wrap(err, "parseMapLiteral", "following map key"))
+			return nil
+		}
+
+		p.nextTokenN(2)
+		pair.Value = p.parseExpression(LOWEST)
+		lit.Pairs = append(lit.Pairs, pair)
+
+		p.skipNewlines()
+		if !p.incrementOnMatch(token.COMMA) {
+			break
+		}
+		p.skipNewlines()
+	}
+
+	if p.peekToken.Type != token.RBRACE {
+		err := cerr.UnexpectedCharError(p.peekToken, token.RBRACE)
+		p.registerError(cerr.Wrap(err, "parseMapLiteral", "closing map literal"))
+		return nil
+	}
+
+	p.nextToken()
+
+	return lit
+}
+
+// parseStructLiteral parses the fields of a struct literal following the name of its type, e.g. Point{x: 1, y: 2}
+func (p *parser) parseStructLiteral(left ast.Expression) ast.Expression {
+	defer p.allowStructLiterals(true)()
+
+	typeName, ok := left.(*ast.IdentifierLiteral)
+	if !ok {
+		err := cerr.UnexpectedTokenError(p.curToken, token.IDENT)
+		p.registerError(cerr.Wrap(err, "parseStructLiteral", "struct literal must follow a type name"))
+		return nil
+	}
+
+	lit := &ast.StructLiteral{Token: *p.curToken, Type: typeName}
+	names := make(map[string]bool)
+
+	p.skipNewlines()
+
+	for p.peekToken.Type != token.RBRACE {
+		if !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+			return nil
+		}
+
+		if names[p.curToken.Literal] {
+			p.registerError(cerr.Wrap(cerr.DuplicateMemberError(p.curToken), "parseStructLiteral"))
+			return nil
+		}
+		names[p.curToken.Literal] = true
+
+		field := &ast.StructLiteralField{Name: &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}}
+
+		if p.peekToken.Type != token.COLON {
+			err := cerr.UnexpectedCharError(p.peekToken, token.COLON)
+			p.registerError(cerr.Wrap(err, "parseStructLiteral", "following field name"))
+			return nil
+		}
+
+		p.nextTokenN(2)
+		field.Value = p.parseExpression(LOWEST)
+		lit.Fields = append(lit.Fields, field)
+
+		p.skipNewlines()
+		if !p.incrementOnMatch(token.COMMA) {
+			break
+		}
+		p.skipNewlines()
+	}
+
+	if p.peekToken.Type != token.RBRACE {
+		err := cerr.UnexpectedCharError(p.peekToken, token.RBRACE)
+		p.registerError(cerr.Wrap(err, "parseStructLiteral", "closing struct literal"))
+		return nil
+	}
+
+	p.nextToken()
+
+	return lit
+}
+
+func (p *parser) parseFieldAccessExpression(left ast.Expression) ast.Expression {
+	exp := &ast.FieldAccessExpression{Token: *p.curToken, Left: left}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+		return nil
+	}
+
+	exp.Field = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
 func (p *parser) parseLBracketExpression(left ast.Expression) ast.Expression {
 	var (
 		sliceParseFn infixParseFn = p.parseSliceLiteralExpression
@@ -337,7 +990,8 @@ func (p *parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp.Index = p.parseExpression(LOWEST)
 
 	if p.peekToken.Type != token.RBRACKET {
-		panic("Not closing array index")
+		p.registerError(cerr.Wrap(cerr.UnexpectedTokenError(p.peekToken, token.RBRACKET), "parseIndexExpression", "closing array index"))
+		return nil
 	}
 
 	p.nextToken()
@@ -362,7 +1016,8 @@ func (p *parser) parseSliceLiteralExpression(left ast.Expression) ast.Expression
 	}
 
 	if p.curToken.Type != token.COLON {
-		panic("No slice literal")
+		p.registerError(cerr.Wrap(cerr.UnexpectedTokenError(p.curToken, token.COLON), "parseSliceLiteralExpression"))
+		return nil
 	}
 
 	p.nextToken()
@@ -371,13 +1026,28 @@ func (p *parser) parseSliceLiteralExpression(left ast.Expression) ast.Expression
 		return exp
 	}
 
-	upper := p.parseExpression(SLICE)
-	exp.Upper = &upper
+	if p.curToken.Type != token.COLON {
+		upper := p.parseExpression(SLICE)
+		exp.Upper = &upper
 
-	p.nextToken()
+		p.nextToken()
+	}
+
+	// the step follows a second colon, e.g. xs[::2]
+	if p.curToken.Type == token.COLON {
+		p.nextToken()
+
+		if p.curToken.Type != token.RBRACKET {
+			step := p.parseExpression(SLICE)
+			exp.Step = &step
+
+			p.nextToken()
+		}
+	}
 
 	if p.curToken.Type != token.RBRACKET {
-		panic("Not closing array index")
+		p.registerError(cerr.Wrap(cerr.UnexpectedTokenError(p.curToken, token.RBRACKET), "parseSliceLiteralExpression", "closing slice"))
+		return nil
 	}
 
 	return exp
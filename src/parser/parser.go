@@ -15,6 +15,8 @@ const (
 	TERNARY
 	EQUALS
 	LESSGREATER
+	COALESCE // a ?? b
+	RANGE    // a..b
 	SUM
 	PRODUCT
 	PREFIX
@@ -24,24 +26,31 @@ const (
 )
 
 var precedences = map[token.Type]int{
-	token.QUESTION: TERNARY,
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.ASSIGN:   ASSIGNMENT,
-	token.LBRACKET: SLICE,
-	token.COLON:    ASSIGNMENT,
+	token.QUESTION:        TERNARY,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.LPAREN:          CALL,
+	token.ASSIGN:          ASSIGNMENT,
+	token.LBRACKET:        SLICE,
+	token.LBRACE:          CALL,
+	token.DOT:             INDEX,
+	token.SAFE_DOT:        INDEX,
+	token.COALESCE:        COALESCE,
+	token.RANGE:           RANGE,
+	token.RANGE_EXCLUSIVE: RANGE,
 }
 
 type Lexer interface {
 	NextToken() *token.Token
 	PeekN(n int) (bool, *token.Token)
+	Offset() int // byte offset following the last token returned by NextToken
+	Source() string
 }
 
 type Parser interface {
@@ -61,8 +70,20 @@ type parser struct {
 	curToken  *token.Token
 	peekToken *token.Token
 
+	curEnd, peekEnd int // byte offsets following the current and peek token in the source
+
 	prefixParseFns map[token.Type]prefixParseFn
 	infixParseFns  map[token.Type]infixParseFn
+
+	loopDepth int // number of loop bodies enclosing the current token within the current function
+
+	// noStructLiteral is set while parsing the header of an if, switch or for, the brace following the header opens
+	// the block so it can't open a struct literal, e.g. if p { } instead of p{}. Brackets reset it.
+	noStructLiteral bool
+
+	// noSingleParameterFunction is set while parsing a contract clause, the arrow following the clause starts the body
+	// of the function it belongs to, e.g. ensure result > x => x + 1 instead of x => x + 1. Brackets reset it.
+	noSingleParameterFunction bool
 }
 
 func New(l Lexer) Parser {
@@ -73,14 +94,21 @@ func New(l Lexer) Parser {
 	p.prefixParseFns = make(map[token.Type]prefixParseFn)
 	p.prefixParseFns[token.INT] = p.parseIntegerLiteral
 	p.prefixParseFns[token.IDENT] = p.parseIdentifier
+	p.prefixParseFns[token.BLANK] = p.parseBlankIdentifier
 	p.prefixParseFns[token.TRUE] = p.parseBooleanLiteral
 	p.prefixParseFns[token.FALSE] = p.parseBooleanLiteral
+	p.prefixParseFns[token.NULL] = p.parseNullLiteral
 	p.prefixParseFns[token.BANG] = p.parsePrefixExpression
 	p.prefixParseFns[token.MINUS] = p.parsePrefixExpression
 	p.prefixParseFns[token.IF] = p.parseIfExpression
+	p.prefixParseFns[token.ELIF] = p.parseMisplacedElif
+	p.prefixParseFns[token.SWITCH] = p.parseSwitchExpression
 	p.prefixParseFns[token.LPAREN] = p.parseLParenExpression
 	p.prefixParseFns[token.STRING_DELIMITER] = p.parseStringLiteral
+	p.prefixParseFns[token.RAW_STRING_DELIMITER] = p.parseStringLiteral
 	p.prefixParseFns[token.LBRACKET] = p.parseArrayLiteral
+	p.prefixParseFns[token.LBRACE] = p.parseMapLiteral
+	p.prefixParseFns[token.LT] = p.parseGenericFunctionLiteral
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
 	p.infixParseFns[token.PLUS] = p.parseInfixExpression
@@ -95,6 +123,12 @@ func New(l Lexer) Parser {
 	p.infixParseFns[token.QUESTION] = p.parseTernaryExpression
 	p.infixParseFns[token.LPAREN] = p.parseCallExpression
 	p.infixParseFns[token.LBRACKET] = p.parseLBracketExpression
+	p.infixParseFns[token.LBRACE] = p.parseStructLiteral
+	p.infixParseFns[token.DOT] = p.parseFieldAccessExpression
+	p.infixParseFns[token.SAFE_DOT] = p.parseFieldAccessExpression
+	p.infixParseFns[token.COALESCE] = p.parseInfixExpression
+	p.infixParseFns[token.RANGE] = p.parseRangeExpression
+	p.infixParseFns[token.RANGE_EXCLUSIVE] = p.parseRangeExpression
 
 	// Set current and peek token
 	p.nextToken()
@@ -130,8 +164,9 @@ func (p *parser) nextTokenN(n int) {
 }
 
 func (p *parser) nextToken() {
-	p.curToken = p.peekToken
+	p.curToken, p.curEnd = p.peekToken, p.peekEnd
 	p.peekToken = p.l.NextToken()
+	p.peekEnd = p.l.Offset()
 }
 
 func (p *parser) peekTokenN(n int) (bool, *token.Token) {
@@ -164,6 +199,13 @@ func (p *parser) incrementOnMatch(t token.Type) bool {
 	return false
 }
 
+// skipNewlines increments until the peek token is no newline
+func (p *parser) skipNewlines() {
+	for p.peekToken.Type == token.NEWLINE {
+		p.nextToken()
+	}
+}
+
 func (p *parser) registerError(err cerr.ParseError) {
 	p.errors = append(p.errors, err)
 }
@@ -176,6 +218,22 @@ func (p *parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.TYPE:
+		return p.parseTypeStatement()
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
+	case token.IDENT, token.BLANK:
+		if p.peekToken.Type == token.COMMA {
+			return p.parsePatternAssignment()
+		}
+		return p.parseExpressionStatement()
+	case token.LBRACKET:
+		if p.isBracketedPattern() {
+			return p.parsePatternAssignment()
+		}
+		return p.parseExpressionStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -184,11 +242,22 @@ func (p *parser) parseStatement() ast.Statement {
 func (p *parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: *p.curToken}
 
-	if !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
-		return nil
-	}
+	if _, next := p.peekTokenN(2); p.peekToken.Type == token.LBRACKET || next != nil && next.Type == token.COMMA {
+		p.nextToken()
 
-	stmt.Name = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+		pattern, err := p.parsePattern()
+		if err != nil {
+			p.registerError(cerr.Wrap(err, "parseLetStatement"))
+			return nil
+		}
+		stmt.Pattern = pattern
+	} else {
+		if !p.incrementOnMatch(token.BLANK) && !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+			return nil
+		}
+
+		stmt.Name = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+	}
 
 	if !p.logOnFailure(p.incrementOnMatch, token.ASSIGN, cerr.UnexpectedTokenError(p.peekToken, token.ASSIGN)) {
 		return nil
@@ -197,6 +266,9 @@ func (p *parser) parseLetStatement() *ast.LetStatement {
 	p.nextToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Pattern != nil && p.peekToken.Type == token.COMMA {
+		stmt.Value = p.parseValueList(stmt.Value)
+	}
 
 	p.incrementOnMatch(token.SEMICOLON)
 
@@ -209,12 +281,463 @@ func (p *parser) parseReturnStatement() *ast.ReturnStatement {
 	p.nextToken()
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
+	if p.peekToken.Type == token.COMMA {
+		stmt.ReturnValue = p.parseValueList(stmt.ReturnValue)
+	}
 
 	p.incrementOnMatch(token.SEMICOLON)
 
 	return stmt
 }
 
+// parseValueList parses the values following the first one of a list, e.g. return a, b or let a, b = 1, 2. The list is
+// an array which a pattern destructures into names again.
+func (p *parser) parseValueList(first ast.Expression) ast.Expression {
+	list := &ast.ArrayLiteral{
+		Token:    *token.New(token.LBRACKET, "[", p.curToken.Pos, p.curToken.Line),
+		Elements: []ast.Expression{first},
+	}
+
+	for p.incrementOnMatch(token.COMMA) {
+		p.nextToken()
+		list.Elements = append(list.Elements, p.parseExpression(LOWEST))
+	}
+
+	return list
+}
+
+// parsePattern parses the names of a pattern starting at its first token, e.g. a, b or [head, ...rest]. Only the last
+// name can be the rest, the pattern stops at it or the closing bracket.
+func (p *parser) parsePattern() (*ast.Pattern, cerr.ParseError) {
+	pattern := &ast.Pattern{Token: *p.curToken, Brackets: p.curToken.Type == token.LBRACKET}
+	if pattern.Brackets {
+		p.nextToken()
+	}
+
+	for {
+		rest := p.curToken.Type == token.ELLIPSIS
+		if rest {
+			p.nextToken()
+		}
+
+		if p.curToken.Type != token.IDENT && p.curToken.Type != token.BLANK {
+			return nil, cerr.Wrap(cerr.UnexpectedTokenError(p.curToken, token.IDENT), "parsePattern")
+		}
+		name := &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+
+		if rest {
+			pattern.Rest = name
+			break
+		}
+		pattern.Names = append(pattern.Names, name)
+
+		if !p.incrementOnMatch(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if pattern.Brackets && !p.incrementOnMatch(token.RBRACKET) {
+		return nil, cerr.Wrap(cerr.UnexpectedCharError(p.peekToken, token.RBRACKET), "parsePattern")
+	}
+
+	return pattern, nil
+}
+
+// isBracketedPattern reports whether the bracket opens a pattern instead of an array literal, it does when the closing
+// bracket is followed by an assignment, e.g. [head, ...rest] = xs
+func (p *parser) isBracketedPattern() bool {
+	depth := 1
+
+	for i := 1; ; i++ {
+		ok, tok := p.peekTokenN(i)
+		if !ok || tok.Type == token.EOF {
+			return false
+		}
+
+		switch tok.Type {
+		case token.LBRACKET:
+			depth++
+		case token.RBRACKET:
+			depth--
+		}
+
+		if depth == 0 {
+			ok, next := p.peekTokenN(i + 1)
+			return ok && next.Type == token.ASSIGN
+		}
+	}
+}
+
+// parsePatternAssignment parses an assignment destructuring the value into a pattern, e.g. a, b = f()
+func (p *parser) parsePatternAssignment() *ast.ExpressionStatement {
+	stmt := &ast.ExpressionStatement{Token: *p.curToken}
+
+	pattern, err := p.parsePattern()
+	if err != nil {
+		p.registerError(cerr.Wrap(err, "parsePatternAssignment"))
+		return nil
+	}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.ASSIGN, cerr.UnexpectedTokenError(p.peekToken, token.ASSIGN)) {
+		return nil
+	}
+
+	assignment := &ast.InfixExpression{Token: *p.curToken, Operator: p.curToken.Literal, Left: pattern}
+
+	p.nextToken()
+
+	assignment.Right = p.parseExpression(LOWEST)
+	if p.peekToken.Type == token.COMMA {
+		assignment.Right = p.parseValueList(assignment.Right)
+	}
+	stmt.Expression = assignment
+
+	p.incrementOnMatch(token.SEMICOLON)
+
+	return stmt
+}
+
+// parseTypeStatement parses a struct or interface declaration, e.g. type Point struct { x int }
+func (p *parser) parseTypeStatement() ast.Statement {
+	stmt := &ast.TypeStatement{Token: *p.curToken}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+		return nil
+	}
+
+	stmt.Name = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+
+	if p.incrementOnMatch(token.LT) {
+		typeParameters, err := p.parseTypeParameters()
+		if err != nil {
+			p.registerError(cerr.Wrap(err, "parseTypeStatement"))
+			return nil
+		}
+		stmt.TypeParameters = typeParameters
+	}
+
+	switch {
+	case p.incrementOnMatch(token.STRUCT):
+		structType := p.parseStructType()
+		if structType == nil {
+			return nil
+		}
+		stmt.Type = structType
+	case p.incrementOnMatch(token.INTERFACE):
+		interfaceType := p.parseInterfaceType()
+		if interfaceType == nil {
+			return nil
+		}
+		stmt.Type = interfaceType
+	default:
+		p.registerError(cerr.Wrap(cerr.UnknownTypeKindError(p.peekToken), "parseTypeStatement"))
+		return nil
+	}
+
+	p.incrementOnMatch(token.SEMICOLON)
+
+	return stmt
+}
+
+// parseStructType parses the body of a struct, fields, methods and invariants are separated by newlines, commas or
+// semicolons:
+//
+//	struct {
+//		x int
+//		y
+//		sum() => self.x + self.y
+//		invariant self.x > 0
+//	}
+func (p *parser) parseStructType() *ast.StructType {
+	structType := &ast.StructType{Token: *p.curToken}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.LBRACE, cerr.UnexpectedTokenError(p.peekToken, token.LBRACE)) {
+		return nil
+	}
+
+	names := make(map[string]bool)
+
+	p.nextToken()
+	for p.curToken.Type != token.RBRACE {
+		switch p.curToken.Type {
+		case token.NEWLINE, token.SEMICOLON, token.COMMA:
+			p.nextToken()
+			continue
+		case token.INVARIANT:
+			invariant := p.parseClause()
+			if invariant == nil {
+				return nil
+			}
+			structType.Invariants = append(structType.Invariants, invariant)
+			p.nextToken()
+			continue
+		case token.IDENT:
+		default:
+			err := cerr.UnexpectedTokenError(p.curToken, token.IDENT)
+			p.registerError(cerr.Wrap(err, "parseStructType", "struct member"))
+			return nil
+		}
+
+		if names[p.curToken.Literal] {
+			p.registerError(cerr.Wrap(cerr.DuplicateMemberError(p.curToken), "parseStructType"))
+			return nil
+		}
+		names[p.curToken.Literal] = true
+
+		name := &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+
+		if p.peekToken.Type == token.LPAREN {
+			p.nextToken()
+
+			function, ok := p.parseFunctionLiteralExpression().(*ast.FunctionLiteralExpression)
+			if !ok {
+				return nil
+			}
+			structType.Methods = append(structType.Methods, &ast.StructMethod{Name: name, Function: function})
+		} else {
+			field := &ast.StructField{Name: name}
+			if p.incrementOnMatch(token.IDENT) {
+				field.Type = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+				field.Nullable = p.incrementOnMatch(token.QUESTION)
+			}
+			structType.Fields = append(structType.Fields, field)
+		}
+
+		p.nextToken()
+	}
+
+	return structType
+}
+
+// parseInterfaceType parses the method signatures of an interface, they are separated by newlines, commas or
+// semicolons:
+//
+//	interface {
+//		area() int
+//		scale(factor int)
+//	}
+func (p *parser) parseInterfaceType() *ast.InterfaceType {
+	interfaceType := &ast.InterfaceType{Token: *p.curToken}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.LBRACE, cerr.UnexpectedTokenError(p.peekToken, token.LBRACE)) {
+		return nil
+	}
+
+	names := make(map[string]bool)
+
+	p.nextToken()
+	for p.curToken.Type != token.RBRACE {
+		switch p.curToken.Type {
+		case token.NEWLINE, token.SEMICOLON, token.COMMA:
+			p.nextToken()
+			continue
+		case token.IDENT:
+		default:
+			err := cerr.UnexpectedTokenError(p.curToken, token.IDENT)
+			p.registerError(cerr.Wrap(err, "parseInterfaceType", "interface method"))
+			return nil
+		}
+
+		if names[p.curToken.Literal] {
+			p.registerError(cerr.Wrap(cerr.DuplicateMemberError(p.curToken), "parseInterfaceType"))
+			return nil
+		}
+		names[p.curToken.Literal] = true
+
+		signature := &ast.Signature{Name: &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}}
+
+		if p.peekToken.Type != token.LPAREN {
+			err := cerr.UnexpectedCharError(p.peekToken, token.LPAREN)
+			p.registerError(cerr.Wrap(err, "parseInterfaceType", "following interface method name"))
+			return nil
+		}
+		p.nextToken()
+
+		parameters, err := p.parseFunctionParameters()
+		if err != nil {
+			p.registerError(cerr.Wrap(err, "parseInterfaceType"))
+			return nil
+		}
+		signature.Parameters = parameters
+
+		// move onto the closing parenthesis of the parameters, the return type follows it
+		p.nextToken()
+		if p.incrementOnMatch(token.IDENT) {
+			signature.ReturnType = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+			signature.ReturnNullable = p.incrementOnMatch(token.QUESTION)
+		}
+
+		interfaceType.Methods = append(interfaceType.Methods, signature)
+
+		p.nextToken()
+	}
+
+	return interfaceType
+}
+
+// parseForStatement parses the infinite, condition, C-style and for in loops:
+//
+//	for { }
+//	for condition { }
+//	for init; condition; post { }
+//	for value, index in iterable { }
+func (p *parser) parseForStatement() ast.Statement {
+	if p.isForInStatement() {
+		return p.parseForInStatement()
+	}
+
+	stmt := &ast.ForStatement{Token: *p.curToken}
+
+	if p.peekToken.Type != token.LBRACE {
+		defer p.allowStructLiterals(false)()
+		p.nextToken()
+
+		var first ast.Statement
+		switch p.curToken.Type {
+		case token.SEMICOLON:
+		case token.LET:
+			first = p.parseLetStatement()
+		default:
+			first = p.parseExpressionStatement()
+		}
+
+		if p.curToken.Type == token.SEMICOLON {
+			stmt.Init = first
+			if !p.parseForClauses(stmt) {
+				return nil
+			}
+		} else if condition, ok := first.(*ast.ExpressionStatement); ok {
+			stmt.Condition = condition.Expression
+		} else {
+			err := cerr.UnexpectedCharError(p.peekToken, token.SEMICOLON)
+			p.registerError(cerr.Wrap(err, "parseForStatement", "following for init statement"))
+			return nil
+		}
+	}
+
+	if stmt.Body = p.parseLoopBody("parseForStatement"); stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseForClauses parses the condition and post statement of a C-style loop, the current token is the semicolon
+// following the init statement
+func (p *parser) parseForClauses(stmt *ast.ForStatement) bool {
+	p.nextToken()
+
+	if p.curToken.Type != token.SEMICOLON {
+		stmt.Condition = p.parseExpression(LOWEST)
+
+		if p.peekToken.Type != token.SEMICOLON {
+			err := cerr.UnexpectedCharError(p.peekToken, token.SEMICOLON)
+			p.registerError(cerr.Wrap(err, "parseForStatement", "following for condition"))
+			return false
+		}
+
+		p.nextToken()
+	}
+
+	if p.peekToken.Type != token.LBRACE {
+		p.nextToken()
+		stmt.Post = p.parseExpressionStatement()
+	}
+
+	return true
+}
+
+// isForInStatement peeks whether the loop binds identifiers, e.g. for x in or for x, i in
+func (p *parser) isForInStatement() bool {
+	if p.peekToken.Type != token.IDENT && p.peekToken.Type != token.BLANK {
+		return false
+	}
+
+	ok, next := p.peekTokenN(2)
+	return ok && (next.Type == token.IN || next.Type == token.COMMA)
+}
+
+func (p *parser) parseForInStatement() ast.Statement {
+	stmt := &ast.ForInStatement{Token: *p.curToken}
+
+	var ok bool
+	if stmt.Value, stmt.Index, stmt.Iterable, ok = p.parseForInHeader(); !ok {
+		return nil
+	}
+
+	if stmt.Body = p.parseLoopBody("parseForInStatement"); stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseForInHeader parses the names bound to the elements and indices and the iterable following for, the index is
+// nil when it is not bound. It is shared by for statements and the for clauses of comprehensions.
+func (p *parser) parseForInHeader() (value, index *ast.IdentifierLiteral, iterable ast.Expression, ok bool) {
+	if !p.incrementOnMatch(token.BLANK) && !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+		return nil, nil, nil, false
+	}
+	value = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+
+	if p.incrementOnMatch(token.COMMA) {
+		if !p.incrementOnMatch(token.BLANK) && !p.logOnFailure(p.incrementOnMatch, token.IDENT, cerr.UnexpectedTokenError(p.peekToken, token.IDENT)) {
+			return nil, nil, nil, false
+		}
+		index = &ast.IdentifierLiteral{Token: *p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.logOnFailure(p.incrementOnMatch, token.IN, cerr.UnexpectedTokenError(p.peekToken, token.IN)) {
+		return nil, nil, nil, false
+	}
+
+	p.nextToken()
+	restore := p.allowStructLiterals(false)
+	iterable = p.parseExpression(LOWEST)
+	restore()
+
+	return value, index, iterable, true
+}
+
+// parseLoopBody parses the block following the loop header, break and continue are allowed inside it
+func (p *parser) parseLoopBody(context string) *ast.BlockStatement {
+	defer p.allowStructLiterals(true)()
+
+	if p.peekToken.Type != token.LBRACE {
+		err := cerr.UnexpectedCharError(p.peekToken, token.LBRACE)
+		p.registerError(cerr.Wrap(err, context, "following for statement"))
+		return nil
+	}
+
+	p.nextToken()
+
+	p.loopDepth++
+	body := p.parseBlockStatement()
+	p.loopDepth--
+
+	p.incrementOnMatch(token.SEMICOLON)
+
+	return body
+}
+
+// parseLoopControlStatement parses break and continue, which are only valid inside a loop of the current function
+func (p *parser) parseLoopControlStatement() ast.Statement {
+	tok := *p.curToken
+
+	if p.loopDepth == 0 {
+		p.registerError(cerr.Wrap(cerr.MisplacedLoopControlError(p.curToken), "parseLoopControlStatement"))
+	}
+
+	p.incrementOnMatch(token.SEMICOLON)
+
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+
+	return &ast.ContinueStatement{Token: tok}
+}
+
 func (p *parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: *p.curToken}
 
@@ -241,7 +764,7 @@ func (p *parser) parseExpression(precedence int) ast.Expression {
 
 	leftExp := prefix()
 
-	for (p.peekToken.Type != token.SEMICOLON && p.peekToken.Type != token.RBRACE) && precedence < precedences[p.peekToken.Type] {
+	for (p.peekToken.Type != token.SEMICOLON && p.peekToken.Type != token.RBRACE) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
 			p.registerError(cerr.Wrap(cerr.MissingParseFnError(p.curToken, cerr.Infix), "parseExpression"))
@@ -257,7 +780,39 @@ func (p *parser) parseExpression(precedence int) ast.Expression {
 
 }
 
+func (p *parser) peekPrecedence() int {
+	if p.peekToken.Type == token.LBRACE && p.noStructLiteral {
+		return LOWEST
+	}
+
+	return precedences[p.peekToken.Type]
+}
+
+// allowStructLiterals sets whether a brace following an expression opens a struct literal, it returns a function
+// restoring the previous setting
+func (p *parser) allowStructLiterals(allow bool) (restore func()) {
+	noStructLiteral := p.noStructLiteral
+	p.noStructLiteral = !allow
+	return func() { p.noStructLiteral = noStructLiteral }
+}
+
+// allowSingleParameterFunctions sets whether an identifier followed by an arrow is the parameter of a function literal,
+// it returns a function restoring the previous setting
+func (p *parser) allowSingleParameterFunctions(allow bool) (restore func()) {
+	noSingleParameterFunction := p.noSingleParameterFunction
+	p.noSingleParameterFunction = !allow
+	return func() { p.noSingleParameterFunction = noSingleParameterFunction }
+}
+
 func (p *parser) parseExpressionList(end token.Type) []ast.Expression {
+	return p.parseList(end, func() ast.Expression { return p.parseExpression(LOWEST) })
+}
+
+// parseList parses the elements separated by commas up to the end token with the given parse function
+func (p *parser) parseList(end token.Type, parse func() ast.Expression) []ast.Expression {
+	defer p.allowStructLiterals(true)()
+	defer p.allowSingleParameterFunctions(true)()
+
 	var list []ast.Expression
 
 	if p.peekToken.Type == end {
@@ -266,12 +821,12 @@ func (p *parser) parseExpressionList(end token.Type) []ast.Expression {
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, parse())
 
 	for p.peekToken.Literal == token.COMMA {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, parse())
 	}
 
 	if p.peekToken.Type != end {
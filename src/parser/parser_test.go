@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"Flow/src/ast"
+	"Flow/src/lexer"
 	"Flow/src/token"
 	"Flow/src/utility/convert"
 
@@ -203,6 +204,283 @@ func (test *Suite) TestOperatorPrecedenceParsing() {
 	}
 }
 
+func (test *Suite) TestElseIfExpressions() {
+	program := CreateProgramFromFile(test.T(), "test_assets/else_if_expressions.flow", 3)
+
+	var tests = []struct {
+		branches    []string // condition and consequence of every branch in the chain
+		alternative []string
+		expected    string
+	}{
+		{
+			branches:    []string{"(x < y)", "alfa", "(x > y)", "beta"},
+			alternative: []string{"gamma"},
+			expected:    "if(x < y) alfaelse if(x > y) betaelse gamma",
+		},
+		{
+			branches:    []string{"(x < y)", "alfa", "(x > y)", "beta", "(x == y)", "gamma"},
+			alternative: nil,
+			expected:    "if(x < y) alfaelse if(x > y) betaelse if(x == y) gamma",
+		},
+		{
+			branches:    []string{"(n > 9)", `"big"`, "(n > 4)", `"medium"`},
+			alternative: []string{`"small"`},
+			expected:    `let size = if(n > 9) "big"else if(n > 4) "medium"else "small";`,
+		},
+	}
+
+	for i, tt := range tests {
+		var expression ast.Expression
+		switch statement := program.Statements[i].(type) {
+		case *ast.ExpressionStatement:
+			expression = statement.Expression
+		case *ast.LetStatement:
+			expression = statement.Value
+		}
+
+		for b := 0; b < len(tt.branches); b += 2 {
+			ifExpression, ok := expression.(*ast.IfExpression)
+			if !ok {
+				test.T().Fatalf("branch %d of statement %d is no *ast.IfExpression; got=%T", b/2, i, expression)
+			}
+
+			test.Equal(tt.branches[b], ifExpression.Condition.String())
+			testBlockStatement(test.T(), ifExpression.Consequence, tt.branches[b+1:b+2])
+
+			if b+2 == len(tt.branches) {
+				if tt.alternative == nil {
+					test.Nil(ifExpression.Alternative)
+				} else {
+					testBlockStatement(test.T(), ifExpression.Alternative, tt.alternative)
+				}
+				break
+			}
+
+			test.Len(ifExpression.Alternative.Statements, 1)
+			expression = ifExpression.Alternative.Statements[0].(*ast.ExpressionStatement).Expression
+		}
+
+		test.Equal(tt.expected, program.Statements[i].String())
+	}
+}
+
+func (test *Suite) TestElseIfExpressions_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if x { 1 } else 2", `1:17: parseIfExpression: following else: expected character "{", got "2" instead`},
+		{"elif x { 1 }", `1:1: parseMisplacedElif: elif must follow an if expression: expected token to be "IF", got "ELIF" instead`},
+		{"if x { 1 } else if { 2 }", `1:24: parseMapLiteral: following map key: expected character ":", got "}" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error())
+		}
+	}
+}
+
+func (test *Suite) TestSwitchExpressions() {
+	program := CreateProgramFromFile(test.T(), "test_assets/switch_expressions.flow", 3)
+
+	type switchCase struct {
+		values []string // nil for default
+		body   []string
+	}
+
+	var tests = []struct {
+		subject  string
+		cases    []switchCase
+		expected string
+	}{
+		{
+			subject:  "x",
+			cases:    []switchCase{{[]string{"1", "2"}, []string{`"low"`}}, {[]string{"3"}, []string{`"three"`, "x"}}, {nil, nil}},
+			expected: `switch x {case 1, 2: "low"case 3: "three"xdefault: }`,
+		},
+		{
+			cases:    []switchCase{{[]string{"(n > 0)"}, []string{"1"}}, {[]string{"(n < 0)"}, []string{"(-1)"}}, {nil, []string{"0"}}},
+			expected: `let sign = switch {case (n > 0): 1case (n < 0): (-1)default: 0};`,
+		},
+		{
+			subject:  "f(x)",
+			cases:    []switchCase{{nil, []string{"x"}}, {[]string{"0"}, nil}},
+			expected: `switch f(x) {default: xcase 0: }`,
+		},
+	}
+
+	for i, tt := range tests {
+		var expression ast.Expression
+		switch statement := program.Statements[i].(type) {
+		case *ast.ExpressionStatement:
+			expression = statement.Expression
+		case *ast.LetStatement:
+			expression = statement.Value
+		}
+
+		switchExpression, ok := expression.(*ast.SwitchExpression)
+		if !ok {
+			test.T().Fatalf("statement %d is no *ast.SwitchExpression; got=%T", i, expression)
+		}
+
+		if tt.subject == "" {
+			test.Nil(switchExpression.Subject)
+		} else {
+			test.Equal(tt.subject, switchExpression.Subject.String())
+		}
+
+		if test.Len(switchExpression.Cases, len(tt.cases)) {
+			for c, expected := range tt.cases {
+				actual := switchExpression.Cases[c]
+				test.Equal(expected.values == nil, actual.IsDefault())
+
+				var values []string
+				for _, value := range actual.Values {
+					values = append(values, value.String())
+				}
+				test.Equal(expected.values, values)
+				testBlockStatement(test.T(), actual.Body, expected.body)
+			}
+		}
+
+		test.Equal(tt.expected, program.Statements[i].String())
+	}
+}
+
+func (test *Suite) TestSwitchExpressions_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch x { 1 }", `1:12: parseSwitchExpression: expected token to be "CASE", got "INT" instead`},
+		{"switch x { case 1 2 }", `1:19: parseSwitchCase: following case: expected character ":", got "2" instead`},
+		{"switch x { default: 1 default: 2 }", `1:23: parseSwitchExpression: multiple defaults in switch`},
+		{"switch x { case 1: 1", `-1:-1: parseSwitchCase: closing switch: expected character "}", got "EOF" instead`},
+		{"switch x 1", `1:10: parseSwitchExpression: following switch subject: expected character "{", got "1" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error())
+		}
+	}
+}
+
+func (test *Suite) TestMapLiterals() {
+	tests := []struct {
+		input    string
+		pairs    int
+		expected string
+	}{
+		{"{}", 0, "{}"},
+		{`{"a": 1, b: 2 + 3}`, 2, `{"a": 1, b: (2 + 3)}`},
+		{"{\n  1: true,\n  false: [1, 2],\n}", 2, "{1: true, false: [1, 2]}"},
+		{`{"f": (x) => x + 1, "m": {"n": 1}}`, 2, `{"f": ((x)return (x + 1);, "m": {"n": 1}}`},
+		{`{"a": 1}["a"]`, 1, `({"a": 1}["a"])`},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+
+		expression := program.Statements[0].(*ast.ExpressionStatement).Expression
+		if index, ok := expression.(*ast.IndexExpression); ok {
+			expression = index.Left
+		}
+
+		mapLiteral, ok := expression.(*ast.MapLiteral)
+		if !ok {
+			test.T().Fatalf("expression is no *ast.MapLiteral; got=%T", expression)
+		}
+
+		test.Len(mapLiteral.Pairs, tt.pairs, tt.input)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+}
+
+func (test *Suite) TestMapLiterals_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"a" 1}`, `1:6: parseMapLiteral: following map key: expected character ":", got "1" instead`},
+		{`{"a": 1 "b": 2}`, `1:9: parseMapLiteral: closing map literal: expected character "}", got "\"" instead`},
+		{`{"a": 1`, `-1:-1: parseMapLiteral: closing map literal: expected character "}", got "EOF" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestForStatements() {
+	program := CreateProgramFromFile(test.T(), "test_assets/for_statements.flow", 6)
+
+	expected := []string{
+		"for {break;}",
+		"for (i < 10) {(i = (i + 1))}",
+		"for let i = 0; (i < 10); (i = (i + 1)) {if(i == 2) continue;(sum = (sum + i))}",
+		"for {}",
+		"for x in [1, 2] {x}",
+		"for _, i in xs {for y in ys {break;}}",
+	}
+
+	for i, statement := range program.Statements {
+		test.Equal(expected[i], statement.String())
+	}
+
+	cStyle, ok := program.Statements[2].(*ast.ForStatement)
+	if test.True(ok) {
+		test.IsType(&ast.LetStatement{}, cStyle.Init)
+		test.Equal("(i < 10)", cStyle.Condition.String())
+		test.Equal("(i = (i + 1))", cStyle.Post.String())
+	}
+
+	forIn, ok := program.Statements[5].(*ast.ForInStatement)
+	if test.True(ok) {
+		test.True(forIn.Value.IsBlank())
+		test.Equal("i", forIn.Index.Value)
+		test.Equal("xs", forIn.Iterable.String())
+	}
+}
+
+func (test *Suite) TestForStatements_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"break", "1:1: parseLoopControlStatement: break is not in a loop"},
+		{"if x { continue }", "1:8: parseLoopControlStatement: continue is not in a loop"},
+		{"for { let f = () => { break } }", "1:23: parseLoopControlStatement: break is not in a loop"},
+		{"for { }; break", "1:10: parseLoopControlStatement: break is not in a loop"},
+		{"for x < 3 x", `1:11: parseForStatement: following for statement: expected character "{", got "x" instead`},
+		{"for let i = 0 { }", `1:15: parseForStatement: following for init statement: expected character ";", got "{" instead`},
+		{"for let i = 0; i < 3 { }", `1:22: parseForStatement: following for condition: expected character ";", got "{" instead`},
+		{"for x, 1 in xs { }", `1:8: expected token to be "IDENT", got "INT" instead`},
+		{"for x, y of xs { }", `1:10: expected token to be "IN", got "IDENT" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
 func (test *Suite) TestIfExpressions() {
 	program := CreateProgramFromFile(test.T(), "test_assets/if_expressions.flow", 2)
 
@@ -338,6 +616,118 @@ func (test *Suite) TestFunctionLiteralExpressions() {
 	}
 }
 
+func (test *Suite) TestExpressionBodiedFunctionLiteralExpressions() {
+	program := CreateProgramFromFile(test.T(), "test_assets/expression_bodied_function_literals.flow", 6)
+
+	var tests = []struct {
+		parameters []string
+		statements []string
+	}{
+		{
+			parameters: []string{"x"},
+			statements: []string{"return (x + 1);"},
+		},
+		{
+			parameters: []string{"a", "b", "c"},
+			statements: []string{"return a?b:c;"},
+		},
+		{
+			parameters: []string{"v", "i"},
+			statements: []string{"return (i == 2);"},
+		},
+		{
+			parameters: []string{},
+			statements: []string{"return ((y)return (y * 2);;"},
+		},
+	}
+
+	for i, tt := range tests {
+		stmt, ok := program.Statements[i].(*ast.LetStatement)
+		if !ok {
+			test.T().Fatalf("statement %d is no *ast.LetStatement; got=%T", i, program.Statements[i])
+		}
+
+		testFunctionLiteralExpression(test.T(), stmt.Value, tt.parameters, tt.statements)
+	}
+
+	call, ok := program.Statements[4].(*ast.LetStatement).Value.(*ast.CallExpression)
+	if !ok {
+		test.T().Fatalf("expected *ast.CallExpression, got=%T", program.Statements[4].(*ast.LetStatement).Value)
+	}
+	test.Len(call.Arguments, 2)
+	testFunctionLiteralExpression(test.T(), call.Arguments[0], []string{"x"}, []string{"return x;"})
+	testIntegerLiteral(test.T(), call.Arguments[1], 2)
+
+	ternary, ok := program.Statements[5].(*ast.LetStatement).Value.(*ast.TernaryExpression)
+	if !ok {
+		test.T().Fatalf("expected *ast.TernaryExpression, got=%T", program.Statements[5].(*ast.LetStatement).Value)
+	}
+	testFunctionLiteralExpression(test.T(), ternary.Consequence, []string{"a"}, []string{"return a;"})
+	testFunctionLiteralExpression(test.T(), ternary.Alternative, []string{"b"}, []string{"return (b + 1);"})
+}
+
+func (test *Suite) TestGroupedExpressionFollowedByFunctionLiteral() {
+	program := CreateProgram(test.T(), "(1 + 2) * 3;\nlet fn = (x) => x;", 2)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		test.T().Fatalf("statement is no *ast.ExpressionStatement; got=%T", program.Statements[0])
+	}
+	test.Equal("((1 + 2) * 3)", stmt.String())
+}
+
+func (test *Suite) TestSingleParameterFunctionLiteralExpressions() {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"let f = x => x + 1", "let f = ((x)return (x + 1);;"},
+		{"let f = x => a ? b : c", "let f = ((x)return a?b:c;;"},
+		{"let f = x => y => x + y", "let f = ((x)return ((y)return (x + y);;;"},
+		{"map(xs, x => x * 2)", "map(xs, ((x)return (x * 2);)"},
+		{"let f = x => { x }", "let f = ((x)x;"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let f = x => a ? b : c", 1)
+	function, ok := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	if !ok {
+		test.T().Fatalf("expected *ast.FunctionLiteralExpression, got=%T", program.Statements[0].(*ast.LetStatement).Value)
+	}
+	test.Equal("1:9", fmt.Sprintf("%d:%d", function.Token.Line, function.Token.Pos))
+	testFunctionLiteralExpression(test.T(), function, []string{"x"}, []string{"return a?b:c;"})
+}
+
+func (test *Suite) TestReturnTypes() {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"(a string) string => a", "((a string) string return a;"},
+		{"let f = (a int) int? => a", "let f = ((a int) int? return a;;"},
+		{"let f = () Shape => { null }", "let f = (() Shape null;"},
+		{"(x int) int require x > 0 => x", "((x int) int require (x > 0) return x;"},
+		{"<T>(a T) T => a", "<T>((a T) T return a;"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let f = (a int) int? => a", 1)
+	function := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	test.Equal("int", function.ReturnType.Value)
+	test.True(function.ReturnNullable)
+
+	program = CreateProgram(test.T(), "(a) * b", 1)
+	test.Equal("(a * b)", program.String())
+}
+
 func (test *Suite) TestParseTemplateMatcher() {
 	var parseFn prefixParseStatementFn
 
@@ -512,6 +902,145 @@ func (test *Suite) TestStringLiteralParsing() {
 	_ = stringLiteral.StringParts
 }
 
+func (test *Suite) TestStringLiteralEscapesParsing() {
+	tests := []struct {
+		input    string
+		parts    []string
+		expected string
+	}{
+		{`"say \"hi\""`, []string{`say "hi"`}, `"say \"hi\""`},
+		{`"a\tb\nc\\"`, []string{"a\tb\nc\\"}, `"a\tb\nc\\"`},
+		{`"\u{48}\u{1F30A}"`, []string{"H🌊"}, `"H🌊"`},
+		{`"\<x> \${y}"`, []string{"<x> ${y}"}, `"\<x> \${y}"`},
+		{`"Value <x>!"`, []string{"Value ", "!"}, `"Value <x>!"`},
+		{`"<(a > b)> and ${c}"`, []string{" and "}, `"<(a > b)> and ${c}"`},
+	}
+
+	for _, tt := range tests {
+		p := CreateProgram(test.T(), tt.input, 1)
+
+		stringLiteral, ok := p.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.StringLiteral)
+		if !ok {
+			test.T().Fatalf("exp not *ast.StringLiteral, got=%T", p.Statements[0])
+		}
+
+		var parts []string
+		link := &stringLiteral.StringParts
+		for {
+			if link.Value.CharacterString != nil {
+				parts = append(parts, *link.Value.CharacterString)
+			}
+			if !link.HasNext() {
+				break
+			}
+			link = link.Next()
+		}
+
+		test.Equal(tt.parts, parts, tt.input)
+		test.Equal(tt.expected, stringLiteral.String())
+	}
+}
+
+func (test *Suite) TestRawStringLiteralParsing() {
+	tests := []struct {
+		input    string
+		parts    []string
+		expected string
+	}{
+		{`"""say "hi" \n <x>"""`, []string{`say "hi" \n <x>`}, `"""say "hi" \n <x>"""`},
+		{`"""  padded"""`, []string{"  padded"}, `"""  padded"""`},
+		{"\"\"\"\n    SELECT *\n      FROM t\n    \"\"\"", []string{"SELECT *\n  FROM t"}, "\"\"\"\nSELECT *\n  FROM t\n\"\"\""},
+		{"\"\"\"\n\t{\n\t\t\"id\": ${id}\n\n\t}\n\t\"\"\"", []string{"{\n\t\"id\": ", "\n\n}"}, "\"\"\"\n{\n\t\"id\": ${id}\n\n}\n\"\"\""},
+		{"\"\"\"\n  ${a} and\n    ${b}\"\"\"", []string{" and\n  "}, "\"\"\"\n${a} and\n  ${b}\n\"\"\""},
+		{`""""""`, []string{""}, `""""""`},
+		{"\"\"\"\n\tone\n    two\n\"\"\"", []string{"\tone\n    two"}, "\"\"\"\n\tone\n    two\n\"\"\""},
+		{"\"\"\"\n\t  a\n\t    b\n\t\"\"\"", []string{"a\n  b"}, "\"\"\"\na\n  b\n\"\"\""},
+		{"\"\"\"\n  \ta\n    b\n\n  \"\"\"", []string{"\ta\n  b\n"}, "\"\"\"\n\ta\n  b\n\n\"\"\""},
+	}
+
+	for _, tt := range tests {
+		p := CreateProgram(test.T(), tt.input, 1)
+
+		stringLiteral, ok := p.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.StringLiteral)
+		if !ok {
+			test.T().Fatalf("exp not *ast.StringLiteral, got=%T", p.Statements[0])
+		}
+		test.Equal(ast.RawString, stringLiteral.Style)
+
+		var parts []string
+		link := &stringLiteral.StringParts
+		for {
+			if link.Value.CharacterString != nil {
+				parts = append(parts, *link.Value.CharacterString)
+			}
+			if !link.HasNext() {
+				break
+			}
+			link = link.Next()
+		}
+
+		test.Equal(tt.parts, parts, tt.input)
+		test.Equal(tt.expected, stringLiteral.String())
+
+		reparsed := CreateProgram(test.T(), stringLiteral.String(), 1)
+		test.Equal(tt.expected, reparsed.Statements[0].String(), "round trip of %s", tt.input)
+	}
+}
+
+func (test *Suite) TestStringLiteralEscapesParsing_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"\q"`, `1:2: parseStringLiteral: invalid string literal "\\q", unknown escape sequence "\q"`},
+		{`"\u{110000}"`, `1:2: parseStringLiteral: invalid string literal "\\u{110000}", invalid unicode code point "\u{110000}"`},
+		{`"open <a"`, `1:9: parseStringTemplate: closing string template: expected token to be "STRING_INTERPOLATION_CLOSE", got "\"" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error())
+		}
+	}
+}
+
+func (test *Suite) TestBlankIdentifierParsing() {
+	p := CreateProgram(test.T(), "let _ = 5; let snake_case = (_, i) => i; _ = snake_case(1, 2);", 3)
+
+	testLetStatement(test.T(), p.Statements[0], "_", 5)
+	test.True(p.Statements[0].(*ast.LetStatement).Name.IsBlank())
+
+	fn := p.Statements[1].(*ast.LetStatement).Value
+	testFunctionLiteralExpression(test.T(), fn, []string{"_", "i"}, []string{"return i;"})
+	test.True(fn.(*ast.FunctionLiteralExpression).Parameters[0].Name.IsBlank())
+	test.False(fn.(*ast.FunctionLiteralExpression).Parameters[1].Name.IsBlank())
+
+	test.Equal("(_ = snake_case(1, 2))", p.Statements[2].String())
+}
+
+func (test *Suite) TestBlankIdentifierParsing_Read() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"_;", `1:1: parseBlankIdentifier: cannot use "_" as value`},
+		{"let a = _ + 1;", `1:9: parseBlankIdentifier: cannot use "_" as value`},
+		{"let f = (_) => _;", `1:16: parseBlankIdentifier: cannot use "_" as value`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error())
+		}
+	}
+}
+
 func (test *Suite) TestArrayLiteralParsing() {
 	input := `[1, 2, 3 + 3, "henk${1}"];`
 	p := CreateProgram(test.T(), input, 1)
@@ -533,7 +1062,7 @@ func (test *Suite) TestArrayLiteralParsing() {
 	testIntegerLiteral(test.T(), array.Elements[0], 1)
 	testIntegerLiteral(test.T(), array.Elements[1], 2)
 	testInfixExpression(test.T(), array.Elements[2], 3, "+", 3)
-	testStringLiteral(test.T(), array.Elements[3], "henk1")
+	testStringLiteral(test.T(), array.Elements[3], `"henk${1}"`)
 }
 
 func (test *Suite) TestIndexExpressionParsing() {
@@ -607,3 +1136,448 @@ func (test *Suite) TestIndexSliceExpressionParsing() {
 		}
 	}
 }
+
+func (test *Suite) TestStructs() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type Point struct {\n  x int\n  y\n  sum() => self.x + self.y\n}", "type Point struct {x int; y; sum() => return (self.x + self.y);}"},
+		{"type Empty struct {}", "type Empty struct {}"},
+		{"Point{x: 1, y: 2 + 3}", "Point{x: 1, y: (2 + 3)}"},
+		{"p.x.y", "p.x.y"},
+		{"p.sum()", "p.sum()"},
+		{"p.x = 3", "(p.x = 3)"},
+		{"[P{x: 1}]", "[P{x: 1}]"},
+		{"if p { 1 }", "ifp 1"},
+		{"if (P{}) == q { 1 }", "if(P{} == q) 1"},
+		{"for x in xs { P{x: x} }", "for x in xs {P{x: x}}"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "type P struct { x; m(a) => a }", 1)
+	statement, ok := program.Statements[0].(*ast.TypeStatement)
+	if test.True(ok) {
+		structType, ok := statement.Type.(*ast.StructType)
+		if test.True(ok) {
+			test.Len(structType.Fields, 1)
+			test.Len(structType.Methods, 1)
+			test.Nil(structType.Fields[0].Type)
+			test.Equal("m", structType.Methods[0].Name.Value)
+		}
+	}
+}
+
+func (test *Suite) TestStructs_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type P struct { x; x }", `1:20: parseStructType: duplicate member "x"`},
+		{"type P struct { 1 }", `1:17: parseStructType: struct member: expected token to be "IDENT", got "INT" instead`},
+		{"type P { x }", `1:8: parseTypeStatement: expected struct or interface, got "{" instead`},
+		{"P{x 1}", `1:5: parseStructLiteral: following field name: expected character ":", got "1" instead`},
+		{"P{x: 1, x: 2}", `1:9: parseStructLiteral: duplicate member "x"`},
+		{"1{x: 1}", `1:2: parseStructLiteral: struct literal must follow a type name: expected token to be "IDENT", got "{" instead`},
+		{"p.1", `1:3: expected token to be "IDENT", got "INT" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestInterfaces() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type Shape interface {\n  area()\n  scale(factor int, _)\n}", "type Shape interface {area(); scale(factor int, _)}"},
+		{"type Empty interface {}", "type Empty interface {}"},
+		{"type Shape interface { area() int; name() string?\n scale(factor int) }", "type Shape interface {area() int; name() string?; scale(factor int)}"},
+		{"let f = (s Shape, n) => s.area()", "let f = ((s Shape, n)return s.area();;"},
+		{"type P struct { m(x int) => x }", "type P struct {m(x int) => return x;}"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let f = (s Shape, n) => n", 1)
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	if test.Len(fn.Parameters, 2) {
+		test.Equal("Shape", fn.Parameters[0].Type.Value)
+		test.Nil(fn.Parameters[1].Type)
+	}
+}
+
+func (test *Suite) TestInterfaces_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type I interface { a(); a() }", `1:25: parseInterfaceType: duplicate member "a"`},
+		{"type I interface { a }", `1:22: parseInterfaceType: following interface method name: expected character "(", got "}" instead`},
+		{"type I interface { 1 }", `1:20: parseInterfaceType: interface method: expected token to be "IDENT", got "INT" instead`},
+		{"type I interface { a(x y z) }", `1:26: parseInterfaceType: parseFunctionParameters: expected character ")", got "z" instead`},
+		{"type I interface { a() int? 1 }", `1:29: parseInterfaceType: interface method: expected token to be "IDENT", got "INT" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestGenerics() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let id = <T>(value T, index int) => value", "let id = <T>((value T, index int)return value;;"},
+		{"<T, U Shape>(a T, b U) => a", "<T, U Shape>((a T, b U)return a;"},
+		{"type Box<T> struct { value T; get() => self.value }", "type Box<T> struct {value T; get() => return self.value;}"},
+		{"type Pair<K, V Shape> struct { k K; v V }", "type Pair<K, V Shape> struct {k K; v V}"},
+		{"a < b", "(a < b)"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "<T, U Shape>(a T, b U) => a", 1)
+	fn := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	if test.Len(fn.TypeParameters, 2) {
+		test.Nil(fn.TypeParameters[0].Constraint)
+		test.Equal("Shape", fn.TypeParameters[1].Constraint.Value)
+	}
+}
+
+func (test *Suite) TestGenerics_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"<>(x) => x", `1:2: parseGenericFunctionLiteral: parseTypeParameters: expected token to be "IDENT", got ">" instead`},
+		{"<T(x) => x", `1:3: parseGenericFunctionLiteral: parseTypeParameters: expected character ">", got "(" instead`},
+		{"<T, T>(x) => x", `1:5: parseGenericFunctionLiteral: parseTypeParameters: duplicate type parameter "T"`},
+		{"<T> x", `1:5: parseFunctionLiteralExpression: following function literal declaration: expected character "(", got "x" instead`},
+		{"type B<T struct { }", `1:10: parseTypeStatement: parseTypeParameters: expected character ">", got "struct" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestNullable() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null", "null"},
+		{"p?.x ?? 0", "(p?.x ?? 0)"},
+		{"a ?? b ?? c", "((a ?? b) ?? c)"},
+		{"x ?? 1 + 2", "(x ?? (1 + 2))"},
+		{"x ?? 1 < 2", "((x ?? 1) < 2)"},
+		{"p?.sum()", "p?.sum()"},
+		{"p?.a.b", "p?.a.b"},
+		{"p?.x = 1", "(p?.x = 1)"},
+		{"c ? a ?? b : null", "c?(a ?? b):null"},
+		{"let f = (x int?, y Shape) => x", "let f = ((x int?, y Shape)return x;;"},
+		{"type P struct { x int?; y }", "type P struct {x int?; y}"},
+		{"type I interface { m(x int?) }", "type I interface {m(x int?)}"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let f = (x int?, y int) => x", 1)
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	if test.Len(fn.Parameters, 2) {
+		test.True(fn.Parameters[0].Nullable)
+		test.False(fn.Parameters[1].Nullable)
+	}
+}
+
+func (test *Suite) TestContracts() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(x) require x > 0 => x", "((x) require (x > 0) return x;"},
+		{"(x int) require x > 0 require x < 9 ensure result > old.x => x * 2",
+			"((x int) require (x > 0) require (x < 9) ensure (result > old.x) return (x * 2);"},
+		{"(x) require valid(x, y => y > 0) ensure result => x", "((x) require valid(x, ((y)return (y > 0);) ensure result return x;"},
+		{"(x) ensure result != null => { x }", "((x) ensure (result != null) x"},
+		{"<T>(a T) ensure result == a => a", "<T>((a T) ensure (result == a) return a;"},
+		{"type A struct { n int; invariant self.n > 0; take(k) require k > 0 => k }",
+			"type A struct {n int; take(k) require (k > 0) => return k;; invariant (self.n > 0)}"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+}
+
+func (test *Suite) TestContractSource() {
+	const input = "let f = (x) require  x>0 ensure result == \"a  b\" => x"
+
+	program := CreateProgram(test.T(), input, 1)
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	test.Equal("require  x>0", fn.Requires[0].Source)
+	test.Equal("ensure result == \"a  b\"", fn.Ensures[0].Source)
+
+	d := NewDocument("let a = 1;\n" + input)
+	test.NoError(d.Edit(8, 9, "10"))
+	fn = d.Program().Statements[1].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	test.Equal("require  x>0", fn.Requires[0].Source)
+
+	program = CreateProgram(test.T(), "type A struct { n int\n  invariant self.n>0 }", 1)
+	structType := program.Statements[0].(*ast.TypeStatement).Type.(*ast.StructType)
+	test.Equal("invariant self.n>0", structType.Invariants[0].Source)
+}
+
+func (test *Suite) TestContracts_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(x) require => x", `1:13: parseExpression: no prefix parse function found for token "=>"`},
+		{"(x) require x > 0 x", `1:19: parseFunctionLiteralExpression: following function literal parameter list declaration: expected character "=>", got "x" instead`},
+		{"type A struct { invariant }", `1:27: parseExpression: no prefix parse function found for token "}"`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestParameters() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = (x, y?) => x", "let f = ((x, y?)return x;;"},
+		{"let f = (x, n int = 10) => x", "let f = ((x, n int = 10)return x;;"},
+		{"let f = (x, n = x + 1, g = () => m, m = 2) => x", "let f = ((x, n = (x + 1), g = (()return m;, m = 2)return x;;"},
+		{"let f = (x, p = Point{x: 1}) => x", "let f = ((x, p = Point{x: 1})return x;;"},
+		{"let f = (first, ...rest int) => rest", "let f = ((first, ...rest int)return rest;;"},
+		{"let f = (x? int?, ...rest) => x", "let f = ((x? int?, ...rest)return x;;"},
+		{"a.b.c", "a.b.c"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let f = (a, b?, c = 1, ...d) => a", 1)
+	fn := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteralExpression)
+	if test.Len(fn.Parameters, 4) {
+		test.True(fn.Parameters[1].Optional)
+		test.NotNil(fn.Parameters[2].Default)
+		test.True(fn.Parameters[3].Variadic)
+	}
+}
+
+func (test *Suite) TestParameters_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(x?, y) => x", `1:6: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "y", a required parameter can't follow an optional one`},
+		{"(...x, y) => x", `1:5: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "x", a variadic parameter has to be the last one`},
+		{"(...x = 1) => x", `1:5: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "x", a variadic parameter can't be optional`},
+		{"(x? = 1) => x", `1:2: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "x", an optional parameter can't have a default value`},
+		{"(n = m, m = 1) => n", `1:2: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "n", its default value refers to parameter m declared after it`},
+		{"(a, n = [a, m + 1], m = 1) => n", `1:5: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "n", its default value refers to parameter m declared after it`},
+		{"(n = n + 1) => n", `1:2: parseFunctionLiteralExpression: parseFunctionParameters: invalid parameter "n", its default value refers to itself`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestPlaceholders() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(_, 2)", "f(_, 2)"},
+		{"f(1, _)", "f(1, _)"},
+		{"f(_)(1)", "f(_)(1)"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	p := New(lexer.New("f(_ + 1)"))
+	p.ParseProgram()
+	if test.NotEmpty(p.Errors()) {
+		test.Equal(`1:3: parseBlankIdentifier: cannot use "_" as value`, p.Errors()[0].Error())
+	}
+}
+
+func (test *Suite) TestPatterns() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a, b = f()", "let a, b = f();"},
+		{"let a, _ = 1, 2", "let a, _ = [1, 2];"},
+		{"let [head, ...rest] = xs", "let [head, ...rest] = xs;"},
+		{"let [a] = xs", "let [a] = xs;"},
+		{"a, b = b, a", "(a, b = [b, a])"},
+		{"[head, ...rest] = xs", "([head, ...rest] = xs)"},
+		{"[a, b][0]", "([a, b][0])"},
+		{"return a, b + 1", "return [a, (b + 1)];"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "let [a, _, ...rest] = xs", 1)
+	pattern := program.Statements[0].(*ast.LetStatement).Pattern
+	if test.NotNil(pattern) && test.Len(pattern.Names, 2) {
+		test.True(pattern.Names[1].IsBlank())
+		test.Equal("rest", pattern.Rest.Value)
+		test.Len(pattern.Identifiers(), 3)
+	}
+}
+
+func (test *Suite) TestPatterns_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, 1] = xs", `1:9: parseLetStatement: parsePattern: expected token to be "IDENT", got "INT" instead`},
+		{"let [...a, b] = xs", `1:10: parseLetStatement: parsePattern: expected character "]", got "," instead`},
+		{"a, b + 1", `1:6: expected token to be "=", got "+" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
+
+func (test *Suite) TestRanges() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0..10", "(0..10)"},
+		{"0..<n", "(0..<n)"},
+		{"1..n + 1", "(1..(n + 1))"},
+		{"0..10 step 2", "(0..10 step 2)"},
+		{"10..0 step -1", "(10..0 step (-1))"},
+		{"for i in 0..<len(xs) { i }", "for i in (0..<len(xs)) {i}"},
+		{"xs[::-1]", "(xs[::(-1)])"},
+		{"xs[1:-1:2]", "(xs[1:(-1):2])"},
+		{"xs[-2:]", "(xs[(-2):])"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "0..<10 step 2", 1)
+	rangeExp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.RangeExpression)
+	if test.True(ok) {
+		test.True(rangeExp.Exclusive)
+		testIntegerLiteral(test.T(), rangeExp.Step, 2)
+	}
+}
+
+func (test *Suite) TestComprehensions() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[x * x for x in xs]", "[(x * x) for x in xs]"},
+		{"[x for x in 0..10 if x > 5]", "[x for x in (0..10) if (x > 5)]"},
+		{"[[x, y] for x in xs if x for y in ys]", "[[x, y] for x in xs if x for y in ys]"},
+		{"[k for v, k in m]", "[k for v, k in m]"},
+		{"[i for _, i in xs]", "[i for _, i in xs]"},
+		{"[P{x: x} for x in xs]", "[P{x: x} for x in xs]"},
+	}
+
+	for _, tt := range tests {
+		program := CreateProgram(test.T(), tt.input, 1)
+		test.Equal(tt.expected, program.String(), tt.input)
+	}
+
+	program := CreateProgram(test.T(), "[x for x in xs if x > 1]", 1)
+	array := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ArrayLiteral)
+	if test.True(array.Generator) && test.Len(array.Clauses, 2) {
+		test.Equal("x", array.Clauses[0].Value.Value)
+		test.Nil(array.Clauses[0].Index)
+		test.Nil(array.Clauses[0].Condition)
+		test.NotNil(array.Clauses[1].Condition)
+	}
+}
+
+func (test *Suite) TestComprehensions_Invalid() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[x for x in xs, 1]", `1:15: parseArrayLiteral: closing comprehension: expected character "]", got "," instead`},
+		{"[1, x for x in xs]", `1:7: parseArrayLiteral: comprehension of more than one element: expected character "]", got "for" instead`},
+		{"[x for x xs]", `1:10: expected token to be "IN", got "IDENT" instead`},
+		{"[x for 1 in xs]", `1:8: expected token to be "IDENT", got "INT" instead`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if test.NotEmpty(p.Errors(), tt.input) {
+			test.Equal(tt.expected, p.Errors()[0].Error(), tt.input)
+		}
+	}
+}
@@ -9,23 +9,17 @@ import (
 )
 
 var (
-	arrowFnRegexp     *regexp.Regexp
-	groupedExprRegexp *regexp.Regexp
-	indexRegexp       *regexp.Regexp
-	sliceRegexp       *regexp.Regexp
+	indexRegexp *regexp.Regexp
+	sliceRegexp *regexp.Regexp
 )
 
 // todo these regexps should be built from atomic pieces
 const (
-	arrowFnRegexpString     = `\(.*\)\s*=>` // whitespaces now optional because they are removed upon lexing
-	groupedExprRegexpString = `\(.+\)`
-	indexRegexpString       = `^\[[^\:]*\]$`
-	sliceRegexpString       = `^\[\s*(\d*|\w*)\s*\:\s*(\d*|\w*)\s*\]$`
+	indexRegexpString = `^\[[^\:]*\]$`
+	sliceRegexpString = `^\[\s*(-?\d*|\w*)\s*\:\s*(-?\d*|\w*)\s*(\:\s*(-?\d*|\w*)\s*)?\]$`
 )
 
 func init() {
-	arrowFnRegexp = regexp.MustCompile(arrowFnRegexpString)
-	groupedExprRegexp = regexp.MustCompile(groupedExprRegexpString)
 	indexRegexp = regexp.MustCompile(indexRegexpString)
 	sliceRegexp = regexp.MustCompile(sliceRegexpString)
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -9,13 +11,67 @@ import (
 	"Flow/src/lexer"
 	"Flow/src/object"
 	"Flow/src/parser"
+	"Flow/src/stdlib"
+	"Flow/src/token"
+	"Flow/src/vet"
 )
 
+const usage = `usage:
+  flow <file>                run the given .flow file
+  flow run [flags] <file>    run the given .flow file
+  flow tokens [flags] <file> print the token stream of the given .flow file, - reads from stdin
+  flow vet <file>            report suspicious constructs in the given .flow file, - reads from stdin
+  flow fmt [flags] <file>    print the formatted source of the given .flow file, - reads from stdin
+`
+
 func main() {
 	if len(os.Args) < 2 {
-		panic("No source file path given!")
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "run":
+		runFile(os.Args[2:])
+	case "tokens":
+		tokens(os.Args[2:])
+	case "vet":
+		vetFile(os.Args[2:])
+	case "fmt":
+		formatFile(os.Args[2:])
+	case "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		run(os.Args[1])
 	}
-	filePath := os.Args[1]
+}
+
+// runFile parses the flags of the run command and runs the file
+func runFile(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	contracts := flags.Bool("contracts", true, "check require, ensure and invariant clauses")
+	seed := flags.Int64("seed", 0, "seed the random module to replay the same random values, random when not set")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "usage: flow run [flags] <file>\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	eval.CheckContracts = *contracts
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			stdlib.Seed(*seed)
+		}
+	})
+	run(flags.Arg(0))
+}
+
+func run(filePath string) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		panic(fmt.Errorf("could not open %s, %w", filePath, err))
@@ -32,3 +88,127 @@ func main() {
 	evaluated := eval.Eval(program, env)
 	fmt.Println(evaluated.Inspect())
 }
+
+func tokens(args []string) {
+	flags := flag.NewFlagSet("tokens", flag.ExitOnError)
+	format := flags.String("format", tableFormat, "output format, either table or json")
+	trivia := flags.Bool("trivia", false, "include trivia tokens like newlines")
+	types := flags.Bool("types", false, "print the names of all token types instead")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "usage: flow tokens [flags] <file>\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if *types {
+		for _, name := range token.Names() {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	source, err := readSource(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := dumpTokens(os.Stdout, source, *format, *trivia); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// vetFile prints the diagnostics of the file, exits with status 1 when the file doesn't parse or has diagnostics
+func vetFile(args []string) {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, "usage: flow vet <file>\n")
+		os.Exit(2)
+	}
+
+	source, err := readSource(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		for _, err := range p.Errors() {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", args[0], err)
+		}
+		os.Exit(1)
+	}
+
+	diagnostics := vet.Vet(program)
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", args[0], diagnostic)
+	}
+
+	if len(diagnostics) > 0 {
+		os.Exit(1)
+	}
+}
+
+// formatFile prints the formatted source of the file or writes it back to the file, exits with status 1 when the file
+// doesn't parse
+func formatFile(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "write the formatted source to the file instead of printing it")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "usage: flow fmt [flags] <file>\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 || (*write && flags.Arg(0) == "-") {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	filePath := flags.Arg(0)
+	source, err := readSource(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := parser.New(lexer.New(source))
+	p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		for _, err := range p.Errors() {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", filePath, err)
+		}
+		os.Exit(1)
+	}
+
+	formatted, err := formatSource(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !*write {
+		fmt.Print(formatted)
+		return
+	}
+
+	if err := os.WriteFile(filePath, []byte(formatted), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readSource reads the source from the file path, - reads from stdin
+func readSource(filePath string) (string, error) {
+	if filePath == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s, %w", filePath, err)
+	}
+	return string(data), nil
+}
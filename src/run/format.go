@@ -0,0 +1,179 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"Flow/src/lexer"
+	"Flow/src/token"
+	"Flow/src/utility/escape"
+)
+
+const indentation = "    "
+
+// opening is a token opening a bracket, string or string template which is still open, indent is the indentation of
+// the line it was opened on
+type opening struct {
+	tokenType token.Type
+	indent    int
+}
+
+// formatter formats source token by token, the source is kept apart from the tokens to see where whitespace was
+type formatter struct {
+	lines  [][]rune
+	out    strings.Builder
+	open   []opening
+	indent int // indentation of the current line
+	blank  int // number of newlines since the last token
+	prev   *token.Token
+}
+
+// formatSource formats the source of a program which parses. Each line is indented by its brackets, whitespace
+// between the tokens of a line collapses into a single space and blank lines into a single blank line. Escape sequences
+// of quoted strings are written in their canonical form, e.g. "\u{48}\x" becomes "H\x" for every character x which has
+// to be escaped.
+func formatSource(source string) (string, error) {
+	f := &formatter{}
+	for _, line := range strings.Split(source, "\n") {
+		f.lines = append(f.lines, []rune(line))
+	}
+
+	l := lexer.New(source)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if err := f.write(tok); err != nil {
+			return "", err
+		}
+	}
+
+	if f.prev != nil {
+		f.out.WriteString("\n")
+	}
+
+	return f.out.String(), nil
+}
+
+func (f *formatter) write(tok *token.Token) error {
+	if tok.Type == token.NEWLINE {
+		if f.prev != nil {
+			f.blank++
+		}
+		return nil
+	}
+
+	inString := f.inString()
+	closing := f.closes(tok)
+
+	switch {
+	case f.prev == nil:
+		f.indent = 0
+	case f.blank > 0:
+		if f.blank > 2 {
+			f.blank = 2
+		}
+		f.out.WriteString(strings.Repeat("\n", f.blank))
+		f.indent = f.lineIndent(closing)
+		f.out.WriteString(strings.Repeat(indentation, f.indent))
+	case f.spaced(tok, inString, closing):
+		f.out.WriteString(" ")
+	}
+	f.blank = 0
+
+	literal := tok.Literal
+	if tok.Type == token.STRING_CHARACTERS && f.top() == token.STRING_DELIMITER {
+		unescaped, err := escape.Unescape(literal)
+		if err != nil {
+			return err
+		}
+		literal = escape.Escape(unescaped)
+	}
+	f.out.WriteString(literal)
+
+	switch {
+	case closing:
+		f.open = f.open[:len(f.open)-1]
+	case opens(tok, inString):
+		f.open = append(f.open, opening{tokenType: tok.Type, indent: f.indent})
+	}
+	f.prev = tok
+
+	return nil
+}
+
+// lineIndent returns the indentation of a line, it is indented one level deeper than the line opening the innermost
+// bracket unless it starts by closing that bracket
+func (f *formatter) lineIndent(closing bool) int {
+	if len(f.open) == 0 {
+		return 0
+	}
+
+	innermost := f.open[len(f.open)-1]
+	if closing {
+		return innermost.indent
+	}
+
+	return innermost.indent + 1
+}
+
+// spaced reports whether the token is separated from the preceding one, it is when the source separates them by
+// whitespace outside a string
+func (f *formatter) spaced(tok *token.Token, inString, closing bool) bool {
+	if inString || (closing && isString(tok.Type)) || f.prev.Type == token.STRING_CHARACTERS {
+		return false
+	}
+	if isString(f.prev.Type) && f.top() == f.prev.Type {
+		return false
+	}
+
+	if tok.Line < 1 || tok.Line > len(f.lines) || tok.Pos < 2 {
+		return false
+	}
+	line := f.lines[tok.Line-1]
+	return tok.Pos-2 < len(line) && unicode.IsSpace(line[tok.Pos-2])
+}
+
+// inString reports whether the next token is part of a string outside of its templates
+func (f *formatter) inString() bool {
+	return isString(f.top())
+}
+
+// closes reports whether the token closes the innermost opening
+func (f *formatter) closes(tok *token.Token) bool {
+	switch f.top() {
+	case token.LPAREN:
+		return tok.Type == token.RPAREN
+	case token.LBRACKET:
+		return tok.Type == token.RBRACKET
+	case token.LBRACE, token.STRING_TEMPLATE_OPEN:
+		return tok.Type == token.RBRACE
+	case token.STRING_INTERPOLATION_OPEN:
+		return tok.Type == token.STRING_INTERPOLATION_CLOSE
+	case token.STRING_DELIMITER, token.RAW_STRING_DELIMITER:
+		return tok.Type == f.top()
+	}
+
+	return false
+}
+
+func (f *formatter) top() token.Type {
+	if len(f.open) == 0 {
+		return ""
+	}
+
+	return f.open[len(f.open)-1].tokenType
+}
+
+// opens reports whether the token opens a bracket, string or string template
+func opens(tok *token.Token, inString bool) bool {
+	switch tok.Type {
+	case token.STRING_TEMPLATE_OPEN, token.STRING_INTERPOLATION_OPEN:
+		return true
+	case token.LPAREN, token.LBRACKET, token.LBRACE, token.STRING_DELIMITER, token.RAW_STRING_DELIMITER:
+		return !inString
+	}
+
+	return false
+}
+
+func isString(t token.Type) bool {
+	return t == token.STRING_DELIMITER || t == token.RAW_STRING_DELIMITER
+}
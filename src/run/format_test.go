@@ -0,0 +1,41 @@
+package main
+
+import (
+	"Flow/src/lexer"
+	"Flow/src/parser"
+)
+
+func (test *Suite) TestFormatSource() {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let   x =  5;let y = x", "let x = 5;let y = x\n"},
+		{"\n\nlet f = (x) => {\nif x {   x  }\n\n\n\n   return   x\n}\n\n", "let f = (x) => {\n    if x { x }\n\n    return x\n}\n"},
+		{"apply((x) => {\nif x {\nx\n}\n}, 1)", "apply((x) => {\n    if x {\n        x\n    }\n}, 1)\n"},
+		{"let s = \"\"\"\n  a\n    b\n\"\"\"\nlet t = s", "let s = \"\"\"\n  a\n    b\n\"\"\"\nlet t = s\n"},
+		{`let s = "a ${ b }c <d> "`, "let s = \"a ${ b }c <d> \"\n"},
+		{`let s = "say \"hi\"\n\t\\ \u{48}\u{1F30A} \<x> \${y} $5"`, "let s = \"say \\\"hi\\\"\\n\\t\\\\ H\U0001F30A \\<x> \\${y} $5\"\n"},
+		{`let s = ""`, "let s = \"\"\n"},
+	}
+
+	for _, tt := range tests {
+		formatted, err := formatSource(tt.input)
+		test.NoError(err, tt.input)
+		test.Equal(tt.expected, formatted, tt.input)
+
+		again, err := formatSource(formatted)
+		test.NoError(err, tt.input)
+		test.Equal(formatted, again, "formatting is not idempotent for %q", tt.input)
+
+		test.Equal(parse(test, tt.input), parse(test, formatted), "formatting changed the program %q", tt.input)
+	}
+}
+
+func parse(test *Suite, source string) string {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	test.Empty(p.Errors(), source)
+
+	return program.String()
+}
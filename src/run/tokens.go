@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"Flow/src/lexer"
+	"Flow/src/token"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+)
+
+// tokenRecord is a single line of json output, field names are part of the output format so keep them stable
+type tokenRecord struct {
+	Type    string `json:"type"`
+	Literal string `json:"literal"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+}
+
+// dumpTokens writes every token of the source up to EOF, trivia tokens are left out unless asked for
+func dumpTokens(w io.Writer, source, format string, trivia bool) error {
+	var write func(tok *token.Token) error
+	flush := func() error { return nil }
+
+	switch format {
+	case tableFormat:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		write = func(tok *token.Token) error {
+			_, err := fmt.Fprintf(tw, "%d:%d\t%s\t%q\n", tok.Line, tok.Pos, tok.Type.Name(), tok.Literal)
+			return err
+		}
+		flush = tw.Flush
+	case jsonFormat:
+		encoder := json.NewEncoder(w)
+		write = func(tok *token.Token) error {
+			return encoder.Encode(tokenRecord{tok.Type.Name(), tok.Literal, tok.Line, tok.Pos})
+		}
+	default:
+		return fmt.Errorf("unknown format %q, expected %q or %q", format, tableFormat, jsonFormat)
+	}
+
+	l := lexer.New(source)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type.IsTrivia() && !trivia {
+			continue
+		}
+		if err := write(tok); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (test *Suite) TestDumpTokens() {
+	source := "let s = \"a ${b}\";\n_"
+
+	tests := []struct {
+		format   string
+		trivia   bool
+		expected string
+	}{
+		{tableFormat, false, `1:1   LET                   "let"
+1:5   IDENT                 "s"
+1:7   ASSIGN                "="
+1:9   STRING_DELIMITER      "\""
+1:10  STRING_CHARACTERS     "a "
+1:12  STRING_TEMPLATE_OPEN  "${"
+1:14  IDENT                 "b"
+1:15  RBRACE                "}"
+1:16  STRING_DELIMITER      "\""
+1:17  SEMICOLON             ";"
+2:1   BLANK                 "_"
+`},
+		{jsonFormat, true, `{"type":"LET","literal":"let","line":1,"col":1}
+{"type":"IDENT","literal":"s","line":1,"col":5}
+{"type":"ASSIGN","literal":"=","line":1,"col":7}
+{"type":"STRING_DELIMITER","literal":"\"","line":1,"col":9}
+{"type":"STRING_CHARACTERS","literal":"a ","line":1,"col":10}
+{"type":"STRING_TEMPLATE_OPEN","literal":"${","line":1,"col":12}
+{"type":"IDENT","literal":"b","line":1,"col":14}
+{"type":"RBRACE","literal":"}","line":1,"col":15}
+{"type":"STRING_DELIMITER","literal":"\"","line":1,"col":16}
+{"type":"SEMICOLON","literal":";","line":1,"col":17}
+{"type":"NEWLINE","literal":"\n","line":1,"col":18}
+{"type":"BLANK","literal":"_","line":2,"col":1}
+`},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := dumpTokens(&out, source, tt.format, tt.trivia)
+		test.NoError(err)
+		test.Equal(tt.expected, out.String(), tt.format)
+	}
+}
+
+func (test *Suite) TestDumpTokens_UnknownFormat() {
+	var out bytes.Buffer
+	err := dumpTokens(&out, "1", "xml", false)
+	test.EqualError(err, `unknown format "xml", expected "table" or "json"`)
+}
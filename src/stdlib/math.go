@@ -0,0 +1,212 @@
+package stdlib
+
+import (
+	"math"
+
+	"Flow/src/object"
+)
+
+func init() {
+	register("math", map[string]function{
+		"abs":   {params(object.INTEGER_OBJ), abs},
+		"min":   {params(object.INTEGER_OBJ, object.INTEGER_OBJ), integerFn(min)},
+		"max":   {params(object.INTEGER_OBJ, object.INTEGER_OBJ), integerFn(max)},
+		"pow":   {params(object.INTEGER_OBJ, object.INTEGER_OBJ), pow},
+		"sqrt":  {params(object.INTEGER_OBJ), sqrt},
+		"clamp": {params(object.INTEGER_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ), clamp},
+		"floor": {params(object.INTEGER_OBJ, object.INTEGER_OBJ), quotient("math.floor", floorDiv)},
+		"ceil":  {params(object.INTEGER_OBJ, object.INTEGER_OBJ), quotient("math.ceil", ceilDiv)},
+		"round": {params(object.INTEGER_OBJ, object.INTEGER_OBJ), quotient("math.round", roundDiv)},
+		"div":   {params(object.INTEGER_OBJ, object.INTEGER_OBJ), quotient("math.div", euclideanDiv)},
+		"mod":   {params(object.INTEGER_OBJ, object.INTEGER_OBJ), division("math.mod", euclideanMod)},
+	})
+}
+
+// abs returns the absolute value, the one of the smallest integer doesn't fit an integer
+func abs(args []object.Object) object.Object {
+	x := integer(args[0])
+	if x == math.MinInt64 {
+		return object.NewEvalErrorObject("integer overflow in \"math.abs\", got=%d", x)
+	}
+	if x < 0 {
+		return &object.Integer{Value: -x}
+	}
+	return args[0]
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// pow raises the base to the exponent by squaring, the exponent cannot be negative as there are only integers. A power
+// which doesn't fit an integer is an error.
+func pow(args []object.Object) object.Object {
+	base, exponent := integer(args[0]), integer(args[1])
+	if exponent < 0 {
+		return object.NewEvalErrorObject("exponent of \"math.pow\" cannot be negative, got=%d", exponent)
+	}
+
+	result, square, ok := int64(1), base, true
+	for e := exponent; e > 0 && ok; e >>= 1 {
+		if e&1 == 1 {
+			result, ok = multiply(result, square)
+		}
+		if e > 1 && ok {
+			square, ok = multiply(square, square)
+		}
+	}
+
+	if !ok {
+		return object.NewEvalErrorObject("integer overflow in \"math.pow\", got=%d^%d", base, exponent)
+	}
+
+	return &object.Integer{Value: result}
+}
+
+// multiply returns the product and whether it fits an integer
+func multiply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
+
+// sqrt is the integer square root, the greatest integer whose square is not greater than x
+func sqrt(args []object.Object) object.Object {
+	x := integer(args[0])
+	if x < 0 {
+		return object.NewEvalErrorObject("argument of \"math.sqrt\" cannot be negative, got=%d", x)
+	}
+
+	// the float root is off by one for large integers, comparing by division keeps the squares from overflowing
+	root := int64(math.Sqrt(float64(x)))
+	for root > 0 && root > x/root {
+		root--
+	}
+	for root+1 <= x/(root+1) {
+		root++
+	}
+
+	return &object.Integer{Value: root}
+}
+
+// clamp limits the value to the bounds, the value is the last argument so math.clamp(0, 9) is a pipeline stage
+func clamp(args []object.Object) object.Object {
+	lower, upper, x := integer(args[0]), integer(args[1]), integer(args[2])
+	if lower > upper {
+		return object.NewEvalErrorObject("lower bound of \"math.clamp\" cannot exceed upper bound, got=%d..%d", lower, upper)
+	}
+
+	return &object.Integer{Value: max(lower, min(upper, x))}
+}
+
+// floorDiv rounds the quotient towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// ceilDiv rounds the quotient towards positive infinity
+func ceilDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) == (b < 0) {
+		q++
+	}
+	return q
+}
+
+// roundDiv rounds the quotient to the nearest integer, halves are rounded away from zero. It rounds the truncated
+// quotient away from zero when the remainder is at least half the divisor, comparing the magnitudes unsigned as the one
+// of the smallest integer doesn't fit an integer.
+func roundDiv(a, b int64) int64 {
+	q, r := a/b, a%b
+	if r == 0 || magnitude(r) < magnitude(b)-magnitude(r) {
+		return q
+	}
+
+	if (a < 0) != (b < 0) {
+		return q - 1
+	}
+	return q + 1
+}
+
+func magnitude(x int64) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
+
+// euclideanDiv and euclideanMod divide so the remainder is never negative, a == b * div(a, b) + mod(a, b) for any sign
+// of a and b
+func euclideanDiv(a, b int64) int64 {
+	q, r := a/b, a%b
+	switch {
+	case r < 0 && b > 0:
+		return q - 1
+	case r < 0:
+		return q + 1
+	default:
+		return q
+	}
+}
+
+func euclideanMod(a, b int64) int64 {
+	r := a % b
+	switch {
+	case r < 0 && b > 0:
+		return r + b
+	case r < 0:
+		return r - b
+	default:
+		return r
+	}
+}
+
+// division lifts the integer division fn to a native function, dividing by zero is an error
+func division(name string, fn func(a, b int64) int64) func(args []object.Object) object.Object {
+	return func(args []object.Object) object.Object {
+		if integer(args[1]) == 0 {
+			return object.NewEvalErrorObject("division by zero in %q", name)
+		}
+		return &object.Integer{Value: fn(integer(args[0]), integer(args[1]))}
+	}
+}
+
+// quotient lifts a rounding integer division like division does, the quotient of the smallest integer by -1 doesn't fit
+// an integer and is an error
+func quotient(name string, fn func(a, b int64) int64) func(args []object.Object) object.Object {
+	divide := division(name, fn)
+	return func(args []object.Object) object.Object {
+		if a, b := integer(args[0]), integer(args[1]); a == math.MinInt64 && b == -1 {
+			return object.NewEvalErrorObject("integer overflow in %q, got=%d / %d", name, a, b)
+		}
+		return divide(args)
+	}
+}
+
+// integerFn lifts a function of two integers to a native function
+func integerFn(fn func(a, b int64) int64) func(args []object.Object) object.Object {
+	return func(args []object.Object) object.Object {
+		return &object.Integer{Value: fn(integer(args[0]), integer(args[1]))}
+	}
+}
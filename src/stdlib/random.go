@@ -0,0 +1,77 @@
+package stdlib
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"Flow/src/object"
+)
+
+// generator is shared by the functions of the random module, seeding it replays the same values
+var generator = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Seed seeds the generator of the random module, runs seeded the same produce the same random values
+func Seed(seed int64) {
+	generator.Seed(seed)
+}
+
+func init() {
+	register("random", map[string]function{
+		"int":     {params(object.INTEGER_OBJ, object.INTEGER_OBJ), randomInt},
+		"choice":  {params(object.ARRAY_OBJ), choice},
+		"shuffle": {params(object.ARRAY_OBJ), shuffle},
+	})
+}
+
+// randomInt returns an integer between the bounds, both bounds included
+func randomInt(args []object.Object) object.Object {
+	lower, upper := integer(args[0]), integer(args[1])
+	if lower > upper {
+		return object.NewEvalErrorObject("lower bound of \"random.int\" cannot exceed upper bound, got=%d..%d", lower, upper)
+	}
+
+	// the span is computed unsigned as it exceeds int64 for bounds far apart, adding the offset wraps back into range
+	span := uint64(upper) - uint64(lower)
+	if span == math.MaxUint64 {
+		return &object.Integer{Value: int64(generator.Uint64())}
+	}
+
+	return &object.Integer{Value: int64(uint64(lower) + uint64n(span+1))}
+}
+
+// uint64n returns an integer in [0, n), n above the range of Int63n is sampled by rejecting values of Uint64 until one
+// is below it which takes less than two tries on average
+func uint64n(n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(generator.Int63n(int64(n)))
+	}
+
+	for {
+		if v := generator.Uint64(); v < n {
+			return v
+		}
+	}
+}
+
+// choice returns a random element of the array
+func choice(args []object.Object) object.Object {
+	elements := args[0].(*object.Array).Elements
+	if len(elements) == 0 {
+		return object.NewEvalErrorObject("cannot choose from an empty array in \"random.choice\"")
+	}
+
+	return elements[generator.Intn(len(elements))]
+}
+
+// shuffle returns a new array of the elements in random order, the array itself is left as it is
+func shuffle(args []object.Object) object.Object {
+	elements := make([]object.Object, len(args[0].(*object.Array).Elements))
+	copy(elements, args[0].(*object.Array).Elements)
+
+	generator.Shuffle(len(elements), func(i, j int) {
+		elements[i], elements[j] = elements[j], elements[i]
+	})
+
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,77 @@
+// Package stdlib holds the native modules of the standard library, importing the package registers them. A function
+// takes the value it works on as its last argument, so given all other arguments it is applied partially and the
+// returned function is a stage of a pipeline e.g. strings.split(","). Operations with operands of their own, like
+// math.pow(base, exponent) or math.div(a, b), take them in the order they are written.
+package stdlib
+
+import (
+	"fmt"
+
+	"Flow/src/object"
+	"Flow/src/token"
+)
+
+// function is a native member of a module, its arguments are checked against the parameter types before it is called
+type function struct {
+	params []object.ObjectType
+	fn     func(args []object.Object) object.Object
+}
+
+// register registers the module of the functions, the errors of a function name it qualified by the module e.g.
+// strings.split
+func register(module string, functions map[string]function) {
+	members := make(map[string]object.Object, len(functions))
+	for name, f := range functions {
+		members[name] = native(module+"."+name, f)
+	}
+
+	object.RegisterModule(&object.Module{Name: module, Members: members})
+}
+
+// native returns the function as native function, it takes exactly as many arguments as the function has parameters.
+// Its errors are positioned at the call.
+func native(name string, f function) *object.NativeFunc {
+	return &object.NativeFunc{
+		Arity: len(f.params),
+		Call: func(tok token.Token, args ...object.Object) object.Object {
+			pos := fmt.Sprintf("%d:%d: ", tok.Line, tok.Pos)
+
+			if len(args) != len(f.params) {
+				return object.NewEvalErrorObject("%sexpected %d argument(s) for %s got=%d", pos, len(f.params), name, len(args))
+			}
+
+			for i, arg := range args {
+				if arg.Type() != f.params[i] {
+					return object.NewEvalErrorObject("%sargument %d to %q must be %s, got=%s", pos, i+1, name, f.params[i], arg.Type())
+				}
+			}
+
+			result := f.fn(args)
+			if err, ok := result.(*object.EvalError); ok {
+				return object.NewEvalErrorObject("%s%s", pos, err.Message)
+			}
+
+			return result
+		},
+	}
+}
+
+// params lists the parameter types of a function
+func params(types ...object.ObjectType) []object.ObjectType {
+	return types
+}
+
+func str(arg object.Object) string {
+	return arg.(*object.String).Value
+}
+
+func integer(arg object.Object) int64 {
+	return arg.(*object.Integer).Value
+}
+
+func nativeBool(b bool) object.Object {
+	if b {
+		return object.TRUE
+	}
+	return object.FALSE
+}
@@ -0,0 +1,132 @@
+package stdlib
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"Flow/src/object"
+)
+
+// maxLength is the largest number of bytes of a string built by repeating, longer results are reported as error instead
+// of exhausting the memory
+const maxLength = 1 << 30
+
+func init() {
+	register("strings", map[string]function{
+		"length":     {params(object.STRING_OBJ), length},
+		"split":      {params(object.STRING_OBJ, object.STRING_OBJ), split},
+		"join":       {params(object.STRING_OBJ, object.ARRAY_OBJ), join},
+		"trim":       {params(object.STRING_OBJ), stringFn(strings.TrimSpace)},
+		"upper":      {params(object.STRING_OBJ), stringFn(strings.ToUpper)},
+		"lower":      {params(object.STRING_OBJ), stringFn(strings.ToLower)},
+		"capitalize": {params(object.STRING_OBJ), stringFn(capitalize)},
+		"contains":   {params(object.STRING_OBJ, object.STRING_OBJ), predicate(strings.Contains)},
+		"startsWith": {params(object.STRING_OBJ, object.STRING_OBJ), predicate(strings.HasPrefix)},
+		"endsWith":   {params(object.STRING_OBJ, object.STRING_OBJ), predicate(strings.HasSuffix)},
+		"replace":    {params(object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ), replace},
+		"repeat":     {params(object.INTEGER_OBJ, object.STRING_OBJ), repeat},
+		"padStart":   {params(object.INTEGER_OBJ, object.STRING_OBJ, object.STRING_OBJ), pad("strings.padStart", true)},
+		"padEnd":     {params(object.INTEGER_OBJ, object.STRING_OBJ, object.STRING_OBJ), pad("strings.padEnd", false)},
+	})
+}
+
+// length counts the characters of the string instead of its bytes
+func length(args []object.Object) object.Object {
+	return &object.Integer{Value: int64(utf8.RuneCountInString(str(args[0])))}
+}
+
+// split splits the string around each separator, an empty separator splits it into its characters
+func split(args []object.Object) object.Object {
+	var elements []object.Object
+	for _, part := range strings.Split(str(args[1]), str(args[0])) {
+		elements = append(elements, &object.String{Value: part})
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// join concatenates the strings of the array with the separator between them
+func join(args []object.Object) object.Object {
+	elements := args[1].(*object.Array).Elements
+
+	parts := make([]string, len(elements))
+	for i, element := range elements {
+		s, ok := element.(*object.String)
+		if !ok {
+			return object.NewEvalErrorObject("elements joined by \"strings.join\" must be STRING, got=%s", element.Type())
+		}
+		parts[i] = s.Value
+	}
+
+	return &object.String{Value: strings.Join(parts, str(args[0]))}
+}
+
+// capitalize turns the first character of the string upper case
+func capitalize(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+
+	return string(unicode.ToUpper(first)) + s[size:]
+}
+
+// replace replaces every occurrence of the old string by the new one
+func replace(args []object.Object) object.Object {
+	return &object.String{Value: strings.ReplaceAll(str(args[2]), str(args[0]), str(args[1]))}
+}
+
+// repeat concatenates count copies of the string
+func repeat(args []object.Object) object.Object {
+	count := integer(args[0])
+	if count < 0 {
+		return object.NewEvalErrorObject("count of \"strings.repeat\" cannot be negative, got=%d", count)
+	}
+
+	if s := str(args[1]); len(s) > 0 && count > maxLength/int64(len(s)) {
+		return object.NewEvalErrorObject("result of \"strings.repeat\" would exceed %d bytes, got count=%d", maxLength, count)
+	}
+
+	return &object.String{Value: strings.Repeat(str(args[1]), int(count))}
+}
+
+// pad pads the string with the padding character to the width in characters, at its start or its end. A string of the
+// width or wider is left as it is.
+func pad(name string, start bool) func(args []object.Object) object.Object {
+	return func(args []object.Object) object.Object {
+		width, padding, s := integer(args[0]), str(args[1]), str(args[2])
+
+		if utf8.RuneCountInString(padding) != 1 {
+			return object.NewEvalErrorObject("padding of %q must be a single character, got=%q", name, padding)
+		}
+
+		missing := width - int64(utf8.RuneCountInString(s))
+		if missing <= 0 {
+			return &object.String{Value: s}
+		}
+
+		if missing > maxLength/int64(len(padding)) {
+			return object.NewEvalErrorObject("result of %q would exceed %d bytes, got width=%d", name, maxLength, width)
+		}
+
+		if start {
+			return &object.String{Value: strings.Repeat(padding, int(missing)) + s}
+		}
+		return &object.String{Value: s + strings.Repeat(padding, int(missing))}
+	}
+}
+
+// stringFn lifts a function transforming a string to a native function
+func stringFn(fn func(string) string) func(args []object.Object) object.Object {
+	return func(args []object.Object) object.Object {
+		return &object.String{Value: fn(str(args[0]))}
+	}
+}
+
+// predicate lifts a function testing the string against a given one, the string it tests is the last argument
+func predicate(fn func(s, substr string) bool) func(args []object.Object) object.Object {
+	return func(args []object.Object) object.Object {
+		return nativeBool(fn(str(args[1]), str(args[0])))
+	}
+}
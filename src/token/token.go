@@ -1,5 +1,7 @@
 package token
 
+import "sort"
+
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
@@ -8,6 +10,7 @@ const (
 	//	Identifiers and literals
 	IDENT = "IDENT"
 	INT   = "INT"
+	BLANK = "_"
 
 	//	Operators
 	ASSIGN   = "="
@@ -18,10 +21,17 @@ const (
 	SLASH    = "/"
 	QUESTION = "?"
 	COLON    = ":"
+	DOT      = "."
+
+	EQ       = "=="
+	NOT_EQ   = "!="
+	ARROW    = "=>"
+	SAFE_DOT = "?."
+	COALESCE = "??"
+	ELLIPSIS = "..."
 
-	EQ     = "=="
-	NOT_EQ = "!="
-	ARROW  = "=>"
+	RANGE           = ".."
+	RANGE_EXCLUSIVE = "..<"
 
 	LT = "<"
 	GT = ">"
@@ -42,12 +52,34 @@ const (
 	FALSE  = "FALSE"
 	IF     = "IF"
 	ELSE   = "ELSE"
+	ELIF   = "ELIF"
 	RETURN = "RETURN"
+	NULL   = "NULL"
+
+	SWITCH  = "SWITCH"
+	CASE    = "CASE"
+	DEFAULT = "DEFAULT"
+
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+
+	TYPE      = "TYPE"
+	STRUCT    = "STRUCT"
+	INTERFACE = "INTERFACE"
+
+	REQUIRE   = "REQUIRE"
+	ENSURE    = "ENSURE"
+	INVARIANT = "INVARIANT"
 
 	// String
-	STRING_DELIMITER     = "\""
-	STRING_CHARACTERS    = "STRING_CHARACTERS"
-	STRING_TEMPLATE_OPEN = "${"
+	STRING_DELIMITER           = "\""
+	RAW_STRING_DELIMITER       = "\"\"\""
+	STRING_CHARACTERS          = "STRING_CHARACTERS"
+	STRING_TEMPLATE_OPEN       = "${"
+	STRING_INTERPOLATION_OPEN  = "STRING_INTERPOLATION_OPEN"  // < inside a string literal
+	STRING_INTERPOLATION_CLOSE = "STRING_INTERPOLATION_CLOSE" // > closing a string interpolation
 
 	// Array
 	LBRACKET = "["
@@ -63,12 +95,116 @@ type Token struct {
 }
 
 var keywords = map[string]Type{
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"let":       LET,
+	"true":      TRUE,
+	"false":     FALSE,
+	"if":        IF,
+	"else":      ELSE,
+	"elif":      ELIF,
+	"return":    RETURN,
+	"switch":    SWITCH,
+	"case":      CASE,
+	"default":   DEFAULT,
+	"for":       FOR,
+	"in":        IN,
+	"break":     BREAK,
+	"continue":  CONTINUE,
+	"type":      TYPE,
+	"struct":    STRUCT,
+	"interface": INTERFACE,
+	"null":      NULL,
+	"require":   REQUIRE,
+	"ensure":    ENSURE,
+	"invariant": INVARIANT,
+	"_":         BLANK,
+}
+
+// names are the stable names of the token types, symbol types have their symbol as value so they need a name
+var names = map[Type]string{
+	ILLEGAL:                    "ILLEGAL",
+	EOF:                        "EOF",
+	UNKNOWN:                    "UNKNOWN",
+	IDENT:                      "IDENT",
+	INT:                        "INT",
+	BLANK:                      "BLANK",
+	ASSIGN:                     "ASSIGN",
+	PLUS:                       "PLUS",
+	MINUS:                      "MINUS",
+	BANG:                       "BANG",
+	ASTERISK:                   "ASTERISK",
+	SLASH:                      "SLASH",
+	QUESTION:                   "QUESTION",
+	COLON:                      "COLON",
+	DOT:                        "DOT",
+	EQ:                         "EQ",
+	NOT_EQ:                     "NOT_EQ",
+	ARROW:                      "ARROW",
+	SAFE_DOT:                   "SAFE_DOT",
+	COALESCE:                   "COALESCE",
+	ELLIPSIS:                   "ELLIPSIS",
+	RANGE:                      "RANGE",
+	RANGE_EXCLUSIVE:            "RANGE_EXCLUSIVE",
+	LT:                         "LT",
+	GT:                         "GT",
+	COMMA:                      "COMMA",
+	SEMICOLON:                  "SEMICOLON",
+	NEWLINE:                    "NEWLINE",
+	LPAREN:                     "LPAREN",
+	RPAREN:                     "RPAREN",
+	LBRACE:                     "LBRACE",
+	RBRACE:                     "RBRACE",
+	LET:                        "LET",
+	TRUE:                       "TRUE",
+	FALSE:                      "FALSE",
+	IF:                         "IF",
+	ELSE:                       "ELSE",
+	ELIF:                       "ELIF",
+	RETURN:                     "RETURN",
+	NULL:                       "NULL",
+	SWITCH:                     "SWITCH",
+	CASE:                       "CASE",
+	DEFAULT:                    "DEFAULT",
+	FOR:                        "FOR",
+	IN:                         "IN",
+	BREAK:                      "BREAK",
+	CONTINUE:                   "CONTINUE",
+	TYPE:                       "TYPE",
+	STRUCT:                     "STRUCT",
+	INTERFACE:                  "INTERFACE",
+	REQUIRE:                    "REQUIRE",
+	ENSURE:                     "ENSURE",
+	INVARIANT:                  "INVARIANT",
+	STRING_DELIMITER:           "STRING_DELIMITER",
+	RAW_STRING_DELIMITER:       "RAW_STRING_DELIMITER",
+	STRING_CHARACTERS:          "STRING_CHARACTERS",
+	STRING_TEMPLATE_OPEN:       "STRING_TEMPLATE_OPEN",
+	STRING_INTERPOLATION_OPEN:  "STRING_INTERPOLATION_OPEN",
+	STRING_INTERPOLATION_CLOSE: "STRING_INTERPOLATION_CLOSE",
+	LBRACKET:                   "LBRACKET",
+	RBRACKET:                   "RBRACKET",
+}
+
+// Name returns the stable name of the token type, e.g. ASSIGN for =
+func (t Type) Name() string {
+	if name, ok := names[t]; ok {
+		return name
+	}
+	return string(t)
+}
+
+// Names returns the sorted names of all token types
+func Names() []string {
+	list := make([]string, 0, len(names))
+	for _, name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// IsTrivia reports whether the token type carries no meaning for the parser beyond layout
+func (t Type) IsTrivia() bool {
+	return t == NEWLINE
 }
 
 // LookupIdentType checks whether input is reserved keyword or identifier
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (test *Suite) TestNames() {
+	test.Equal("ASSIGN", Type(ASSIGN).Name())
+	test.Equal("RAW_STRING_DELIMITER", Type(RAW_STRING_DELIMITER).Name())
+	test.Equal("LET", LookupIdentType("let").Name())
+	test.Equal("BLANK", LookupIdentType("_").Name())
+
+	seen := make(map[string]bool)
+	for _, name := range Names() {
+		test.False(seen[name], "duplicate token type name %s", name)
+		seen[name] = true
+	}
+	test.Len(seen, len(names))
+}
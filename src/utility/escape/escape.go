@@ -0,0 +1,107 @@
+package escape
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Unescape replaces the escape sequences of a raw string literal part by the characters they represent
+// supported are \n \t \r \" \\ \< \$ and \u{...} holding the hexadecimal code point of a unicode character
+func Unescape(raw string) (string, error) {
+	if !strings.ContainsRune(raw, '\\') {
+		return raw, nil
+	}
+
+	var out strings.Builder
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			out.WriteByte(raw[i])
+			continue
+		}
+
+		if i+1 >= len(raw) {
+			return "", fmt.Errorf("unterminated escape sequence %q", raw[i:])
+		}
+
+		i++
+		switch raw[i] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '"', '\\', '<', '$':
+			out.WriteByte(raw[i])
+		case 'u':
+			ch, length, err := unescapeUnicode(raw[i+1:])
+			if err != nil {
+				return "", err
+			}
+			out.WriteRune(ch)
+			i += length
+		default:
+			return "", fmt.Errorf("unknown escape sequence \"\\%c\"", raw[i])
+		}
+	}
+
+	return out.String(), nil
+}
+
+// unescapeUnicode parses the {...} part of a \u{...} escape sequence, returns the rune and the amount of bytes consumed
+func unescapeUnicode(s string) (rune, int, error) {
+	end := strings.IndexByte(s, '}')
+	if !strings.HasPrefix(s, "{") || end < 2 || end > 7 {
+		return 0, 0, fmt.Errorf("invalid unicode escape sequence \"\\u%s\", expected \\u{...} with 1 to 6 hexadecimal digits", firstN(s, 8))
+	}
+
+	code, err := strconv.ParseUint(s[1:end], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, 0, fmt.Errorf("invalid unicode code point \"\\u%s\"", s[:end+1])
+	}
+
+	return rune(code), end + 1, nil
+}
+
+func firstN(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// Escape is the inverse of Unescape, it escapes characters which can not appear unescaped in a string literal
+func Escape(s string) string {
+	var out strings.Builder
+
+	for i, ch := range s {
+		switch ch {
+		case '\n':
+			out.WriteString(`\n`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '"', '\\', '<':
+			out.WriteRune('\\')
+			out.WriteRune(ch)
+		case '$':
+			if strings.HasPrefix(s[i+1:], "{") {
+				out.WriteRune('\\')
+			}
+			out.WriteRune(ch)
+		default:
+			if unicode.IsPrint(ch) || ch == ' ' {
+				out.WriteRune(ch)
+			} else {
+				out.WriteString(fmt.Sprintf(`\u{%X}`, ch))
+			}
+		}
+	}
+
+	return out.String()
+}
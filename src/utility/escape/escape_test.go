@@ -0,0 +1,70 @@
+package escape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`plain`, "plain"},
+		{`say \"hi\"`, `say "hi"`},
+		{`line\nbreak`, "line\nbreak"},
+		{`tab\tstop\r`, "tab\tstop\r"},
+		{`back\\slash`, `back\slash`},
+		{`\<x> and \${x}`, `<x> and ${x}`},
+		{`\u{48}\u{49}`, "HI"},
+		{`\u{1F30A}`, "🌊"},
+	}
+
+	for _, tt := range tests {
+		result, err := Unescape(tt.input)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expected, result)
+	}
+}
+
+func TestUnescape_Invalid(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`\q`, `unknown escape sequence "\q"`},
+		{`trailing\`, `unterminated escape sequence "\\"`},
+		{`\u48`, `invalid unicode escape sequence "\u48", expected \u{...} with 1 to 6 hexadecimal digits`},
+		{`\u{}`, `invalid unicode escape sequence "\u{}", expected \u{...} with 1 to 6 hexadecimal digits`},
+		{`\u{ZZ}`, `invalid unicode code point "\u{ZZ}"`},
+		{`\u{D800}`, `invalid unicode code point "\u{D800}"`},
+	}
+
+	for _, tt := range tests {
+		_, err := Unescape(tt.input)
+		if assert.NotNil(t, err, tt.input) {
+			assert.Equal(t, tt.expected, err.Error())
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []string{
+		"plain",
+		`say "hi"`,
+		"line\nbreak\ttab",
+		`back\slash`,
+		"<x> and ${x} but $5",
+		"bell\u0007",
+	}
+
+	for _, input := range tests {
+		unescaped, err := Unescape(Escape(input))
+		assert.Nil(t, err)
+		assert.Equal(t, input, unescaped)
+	}
+
+	assert.Equal(t, `say \"hi\"\n`, Escape("say \"hi\"\n"))
+	assert.Equal(t, `\<x> \${x} $5`, Escape("<x> ${x} $5"))
+}
@@ -0,0 +1,553 @@
+package vet
+
+import (
+	"fmt"
+
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+)
+
+// argumentTypes reports arguments not implementing the interface of the parameter they are passed to or not matching
+// its builtin or struct type, arguments or struct literal fields conflicting with the type arguments inferred for a
+// generic function or struct or not matching the builtin type of the field, and null for a type which isn't nullable.
+// Only functions and types declared by top level statements are checked, with values whose type is known without
+// evaluating them: literals and identifiers bound to a struct literal by a top level let. Names declared or assigned
+// more than once are skipped as their type depends on the scope. Calls of the builtins filter and reduce are checked
+// against their generic signatures unless the program declares the name itself.
+func argumentTypes(node ast.Node, report func(tok token.Token, format string, args ...interface{})) {
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return
+	}
+
+	d := declare(program)
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpression:
+			d.checkCall(node, report)
+		case *ast.StructLiteral:
+			d.checkStructLiteral(node, report)
+		}
+		return true
+	})
+}
+
+func (d *declarations) checkCall(call *ast.CallExpression, report func(tok token.Token, format string, args ...interface{})) {
+	function, ok := call.Function.(*ast.IdentifierLiteral)
+	if !ok {
+		return
+	}
+
+	if signature, ok := builtinSignatures[function.Value]; ok && d.counts[function.Value] == 0 {
+		d.checkBuiltinCall(function.Value, signature, call, report)
+		return
+	}
+
+	if !d.unique(function.Value) {
+		return
+	}
+
+	declaration, ok := d.functions[function.Value]
+	if !ok {
+		return
+	}
+
+	inferred := d.inference(declaration.TypeParameters)
+
+	for i, argument := range call.Arguments {
+		parameter, ok := ast.ParameterAt(declaration.Parameters, i)
+		if !ok || parameter.Type == nil {
+			continue
+		}
+
+		typeName, tok, ok := d.staticType(argument)
+		if !ok {
+			continue
+		}
+
+		if typeName == object.NULL_OBJ {
+			if !parameter.AcceptsNull() {
+				report(tok, "argument %s: %s is not nullable", parameter.Name, parameter.Type)
+			}
+			continue
+		}
+
+		if _, ok := inferred.parameters[parameter.Type.Value]; ok {
+			if mismatch := inferred.bind(parameter.Type.Value, typeName); mismatch != "" {
+				report(tok, "argument %s: %s", parameter.Name, mismatch)
+			}
+			continue
+		}
+
+		if mismatch := d.matchType(parameter.Type.Value, typeName); mismatch != "" {
+			report(tok, "argument %s: %s", parameter.Name, mismatch)
+		}
+	}
+}
+
+// builtinSignature is the generic signature of a builtin calling the function passed as its first argument with the
+// elements of the values passed as its last argument, e.g. filter<T>(predicate (value T, index) bool, values T[]) T[]
+// and reduce<T, A>(reducer (acc A, value T, index) A, initial A, values T[]) A
+type builtinSignature struct {
+	parameters []string
+	element    int    // the parameter of the function taking the element T, the accumulated value A precedes it
+	returns    string // the type the function returns, A when it is empty
+}
+
+var builtinSignatures = map[string]builtinSignature{
+	"filter": {parameters: []string{"predicate", "values"}, element: 0, returns: object.BOOLEAN_OBJ},
+	"reduce": {parameters: []string{"reducer", "initial", "values"}, element: 1},
+}
+
+// checkBuiltinCall checks the arguments of a builtin against its signature, the type arguments are inferred from the
+// types the function declares and the static types of the initial value and of the elements of an array literal
+func (d *declarations) checkBuiltinCall(name string, signature builtinSignature, call *ast.CallExpression, report func(tok token.Token, format string, args ...interface{})) {
+	arguments := call.Arguments
+	if len(arguments) == 0 || len(arguments) > len(signature.parameters) {
+		return
+	}
+
+	inferred := d.inference([]*ast.TypeParameter{
+		{Name: &ast.IdentifierLiteral{Value: "T"}},
+		{Name: &ast.IdentifierLiteral{Value: "A"}},
+	})
+
+	var element, acc *ast.Parameter
+	fn, tok, ok := d.functionLiteral(arguments[0])
+	switch {
+	case ok:
+		element, acc = d.checkCallback(name, signature, fn, tok, inferred, report)
+	default:
+		if typeName, tok, ok := d.staticType(arguments[0]); ok {
+			report(tok, "argument %s: %s is not a function", signature.parameters[0], typeName)
+		}
+	}
+
+	if signature.returns == "" && len(arguments) > 1 {
+		if typeName, tok, ok := d.staticType(arguments[1]); ok {
+			d.checkTypeArgument(signature.parameters[1], "A", acc, typeName, tok, inferred, report)
+		}
+	}
+
+	if len(arguments) < len(signature.parameters) {
+		return
+	}
+
+	values := arguments[len(arguments)-1]
+	valuesName := signature.parameters[len(signature.parameters)-1]
+	typeName, tok, ok := d.staticType(values)
+	if !ok {
+		return
+	}
+	if typeName != object.ARRAY_OBJ && typeName != object.MAP_OBJ {
+		report(tok, "argument %s: %s is not iterable", valuesName, typeName)
+		return
+	}
+
+	if array, ok := values.(*ast.ArrayLiteral); ok {
+		for _, e := range array.Elements {
+			if typeName, tok, ok := d.staticType(e); ok {
+				d.checkTypeArgument(valuesName, "T", element, typeName, tok, inferred, report)
+			}
+		}
+	}
+}
+
+// checkCallback checks the function passed to a builtin, it returns its parameters taking the element and the
+// accumulated value when it declares them
+func (d *declarations) checkCallback(name string, signature builtinSignature, fn *ast.FunctionLiteralExpression, tok token.Token, inferred *inference, report func(tok token.Token, format string, args ...interface{})) (element, acc *ast.Parameter) {
+	parameterName := signature.parameters[0]
+
+	offered := signature.element + 2
+	if least, _ := ast.Arity(fn.Parameters); least > offered {
+		report(tok, "argument %s: function takes at least %d arguments, %s passes %d", parameterName, least, name, offered)
+		return nil, nil
+	}
+
+	element, _ = ast.ParameterAt(fn.Parameters, signature.element)
+	if element != nil {
+		d.bindDeclared(parameterName, "T", element.Type, tok, inferred, report)
+	}
+
+	if signature.returns != "" {
+		returns, ok := d.concreteType(fn.ReturnType)
+		if fn.ReturnType == nil {
+			returns, ok = d.returnedType(fn)
+		}
+		if ok && returns != signature.returns {
+			report(tok, "argument %s: function returns %s, %s needs %s", parameterName, returns, name, signature.returns)
+		}
+		return element, nil
+	}
+
+	acc, _ = ast.ParameterAt(fn.Parameters, 0)
+	if acc != nil {
+		d.bindDeclared(parameterName, "A", acc.Type, tok, inferred, report)
+	}
+	d.bindDeclared(parameterName, "A", fn.ReturnType, tok, inferred, report)
+
+	return element, acc
+}
+
+// returnedType returns the static type of the value returned by a function without a declared return type whose body
+// is a single return statement, like the body of x => x > 1
+func (d *declarations) returnedType(fn *ast.FunctionLiteralExpression) (string, bool) {
+	if fn.Body == nil || len(fn.Body.Statements) != 1 {
+		return "", false
+	}
+
+	ret, ok := fn.Body.Statements[0].(*ast.ReturnStatement)
+	if !ok || ret.ReturnValue == nil {
+		return "", false
+	}
+
+	typeName, _, ok := d.staticType(ret.ReturnValue)
+	return typeName, ok
+}
+
+// bindDeclared infers the type parameter from a builtin or struct type declared by the function
+func (d *declarations) bindDeclared(parameterName, typeParameter string, declared *ast.IdentifierLiteral, tok token.Token, inferred *inference, report func(tok token.Token, format string, args ...interface{})) {
+	typeName, ok := d.concreteType(declared)
+	if !ok {
+		return
+	}
+
+	if mismatch := inferred.bind(typeParameter, typeName); mismatch != "" {
+		report(tok, "argument %s: %s", parameterName, mismatch)
+	}
+}
+
+// checkTypeArgument checks a value of the type parameter against the parameter of the function taking it. Values of a
+// type parameter the function leaves untyped may be of any type, those of an interface have to implement it.
+func (d *declarations) checkTypeArgument(parameterName, typeParameter string, parameter *ast.Parameter, typeName string, tok token.Token, inferred *inference, report func(tok token.Token, format string, args ...interface{})) {
+	if typeName == object.NULL_OBJ {
+		if parameter != nil && !parameter.AcceptsNull() {
+			report(tok, "argument %s: %s is not nullable", parameterName, parameter.Type)
+		}
+		return
+	}
+
+	if _, ok := inferred.inferred[typeParameter]; !ok {
+		if parameter == nil || parameter.Type == nil {
+			return
+		}
+
+		if _, ok := d.concreteType(parameter.Type); !ok {
+			if mismatch := d.matchType(parameter.Type.Value, typeName); mismatch != "" {
+				report(tok, "argument %s: %s", parameterName, mismatch)
+			}
+			return
+		}
+	}
+
+	if mismatch := inferred.bind(typeParameter, typeName); mismatch != "" {
+		report(tok, "argument %s: %s", parameterName, mismatch)
+	}
+}
+
+// concreteType returns the name of the static type of the values of a declared builtin or struct type
+func (d *declarations) concreteType(declared *ast.IdentifierLiteral) (string, bool) {
+	if declared == nil {
+		return "", false
+	}
+
+	if builtin, ok := object.BuiltinType(declared.Value); ok {
+		return string(builtin), true
+	}
+
+	if _, ok := d.structs[declared.Value]; ok && d.unique(declared.Value) {
+		return declared.Value, true
+	}
+
+	return "", false
+}
+
+// functionLiteral returns the function an expression evaluates to when it is a function literal or the name of one
+// declared by a top level let
+func (d *declarations) functionLiteral(expression ast.Expression) (*ast.FunctionLiteralExpression, token.Token, bool) {
+	switch expression := expression.(type) {
+	case *ast.FunctionLiteralExpression:
+		return expression, expression.Token, true
+	case *ast.IdentifierLiteral:
+		if fn, ok := d.functions[expression.Value]; ok && d.unique(expression.Value) {
+			return fn, expression.Token, true
+		}
+	}
+
+	return nil, token.Token{}, false
+}
+
+// matchType describes why a value of the type doesn't match the declared type, it has to implement an interface and
+// match builtin and struct types exactly. The description is empty when it matches or the declared type is unknown.
+func (d *declarations) matchType(declared, typeName string) string {
+	if builtin, ok := object.BuiltinType(declared); ok {
+		if string(builtin) != typeName {
+			return fmt.Sprintf("%s is not of type %s", typeName, declared)
+		}
+		return ""
+	}
+
+	if !d.unique(declared) {
+		return ""
+	}
+
+	if signatures, ok := d.interfaces[declared]; ok {
+		return d.implements(typeName, declared, signatures)
+	}
+
+	if _, ok := d.structs[declared]; ok && typeName != declared {
+		return fmt.Sprintf("%s is not of type %s", typeName, declared)
+	}
+
+	return ""
+}
+
+func (d *declarations) checkStructLiteral(literal *ast.StructLiteral, report func(tok token.Token, format string, args ...interface{})) {
+	if !d.unique(literal.Type.Value) {
+		return
+	}
+
+	declaration, ok := d.structs[literal.Type.Value]
+	if !ok {
+		return
+	}
+
+	inferred := d.inference(declaration.typeParameters)
+
+	for _, field := range literal.Fields {
+		fieldType, ok := declaration.fieldTypes[field.Name.Value]
+		if !ok {
+			continue
+		}
+
+		typeName, _, ok := d.staticType(field.Value)
+		if !ok {
+			continue
+		}
+
+		if typeName == object.NULL_OBJ {
+			if !fieldType.Nullable {
+				report(field.Name.Token, "field %s: %s is not nullable", field.Name, fieldType.Type)
+			}
+			continue
+		}
+
+		if _, ok := inferred.parameters[fieldType.Type.Value]; ok {
+			if mismatch := inferred.bind(fieldType.Type.Value, typeName); mismatch != "" {
+				report(field.Name.Token, "field %s: %s", field.Name, mismatch)
+			}
+			continue
+		}
+
+		if builtin, ok := object.BuiltinType(fieldType.Type.Value); ok && string(builtin) != typeName {
+			report(field.Name.Token, "field %s: %s is not of type %s", field.Name, typeName, fieldType.Type)
+		}
+	}
+
+	for _, field := range declaration.fields {
+		if field.Type != nil && !field.Nullable && !literal.HasField(field.Name.Value) {
+			report(literal.Type.Token, "field %s: %s is not nullable", field.Name, field.Type)
+		}
+	}
+}
+
+// structDeclaration is what the checks need to know of a struct declaration
+type structDeclaration struct {
+	typeParameters []*ast.TypeParameter
+	fields         []*ast.StructField
+	fieldTypes     map[string]*ast.StructField // the fields declaring a type
+	methods        map[string]*ast.Signature
+}
+
+// declarations are the types, functions and struct values declared by the top level statements of a program
+type declarations struct {
+	structs    map[string]*structDeclaration
+	interfaces map[string][]*ast.Signature
+	functions  map[string]*ast.FunctionLiteralExpression
+	values     map[string]string
+	// counts how often a name is declared or assigned anywhere in the program
+	counts map[string]int
+}
+
+func declare(program *ast.Program) *declarations {
+	d := &declarations{
+		structs:    make(map[string]*structDeclaration),
+		interfaces: make(map[string][]*ast.Signature),
+		functions:  make(map[string]*ast.FunctionLiteralExpression),
+		values:     make(map[string]string),
+		counts:     make(map[string]int),
+	}
+
+	for _, statement := range program.Statements {
+		switch statement := statement.(type) {
+		case *ast.TypeStatement:
+			switch declaration := statement.Type.(type) {
+			case *ast.StructType:
+				s := &structDeclaration{
+					typeParameters: statement.TypeParameters,
+					fields:         declaration.Fields,
+					fieldTypes:     make(map[string]*ast.StructField),
+					methods:        make(map[string]*ast.Signature),
+				}
+				for _, field := range declaration.Fields {
+					if field.Type != nil {
+						s.fieldTypes[field.Name.Value] = field
+					}
+				}
+				for _, method := range declaration.Methods {
+					s.methods[method.Name.Value] = &ast.Signature{
+						Name:           method.Name,
+						Parameters:     method.Function.Parameters,
+						ReturnType:     method.Function.ReturnType,
+						ReturnNullable: method.Function.ReturnNullable,
+					}
+				}
+				d.structs[statement.Name.Value] = s
+			case *ast.InterfaceType:
+				d.interfaces[statement.Name.Value] = declaration.Methods
+			}
+		case *ast.LetStatement:
+			if statement.Pattern != nil {
+				break
+			}
+			switch value := statement.Value.(type) {
+			case *ast.FunctionLiteralExpression:
+				d.functions[statement.Name.Value] = value
+			case *ast.StructLiteral:
+				d.values[statement.Name.Value] = value.Type.Value
+			}
+		}
+	}
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.TypeStatement:
+			d.counts[node.Name.Value]++
+		case *ast.LetStatement:
+			for _, name := range node.Names() {
+				d.counts[name.Value]++
+			}
+		case *ast.FunctionLiteralExpression:
+			for _, parameter := range node.Parameters {
+				d.counts[parameter.Name.Value]++
+			}
+		case *ast.ForInStatement:
+			d.counts[node.Value.Value]++
+			if node.Index != nil {
+				d.counts[node.Index.Value]++
+			}
+		case *ast.ComprehensionClause:
+			if node.Value != nil {
+				d.counts[node.Value.Value]++
+			}
+			if node.Index != nil {
+				d.counts[node.Index.Value]++
+			}
+		case *ast.InfixExpression:
+			if identifier, ok := node.Left.(*ast.IdentifierLiteral); ok && node.Operator == "=" {
+				d.counts[identifier.Value]++
+			}
+			if pattern, ok := node.Left.(*ast.Pattern); ok && node.Operator == "=" {
+				for _, identifier := range pattern.Identifiers() {
+					d.counts[identifier.Value]++
+				}
+			}
+		}
+		return true
+	})
+
+	return d
+}
+
+// unique reports whether the name is declared once and never assigned, so it refers to the same declaration everywhere
+func (d *declarations) unique(name string) bool {
+	return d.counts[name] == 1
+}
+
+// implements describes why the type doesn't implement the interface, it is empty when it does
+func (d *declarations) implements(typeName, interfaceName string, signatures []*ast.Signature) string {
+	var methods map[string]*ast.Signature
+	if s, ok := d.structs[typeName]; ok {
+		methods = s.methods
+	}
+
+	if mismatch := ast.Implements(signatures, methods); mismatch != "" {
+		return fmt.Sprintf("%s does not implement %s: %s", typeName, interfaceName, mismatch)
+	}
+
+	return ""
+}
+
+// staticType returns the name of the type of an expression when it is known without evaluating it
+func (d *declarations) staticType(expression ast.Expression) (string, token.Token, bool) {
+	switch expression := expression.(type) {
+	case *ast.StructLiteral:
+		if _, ok := d.structs[expression.Type.Value]; ok && d.unique(expression.Type.Value) {
+			return expression.Type.Value, expression.Type.Token, true
+		}
+	case *ast.IdentifierLiteral:
+		typeName, ok := d.values[expression.Value]
+		if ok && d.unique(expression.Value) && d.unique(typeName) {
+			if _, ok := d.structs[typeName]; ok {
+				return typeName, expression.Token, true
+			}
+		}
+	case *ast.IntegerLiteral:
+		return object.INTEGER_OBJ, expression.Token, true
+	case *ast.BooleanLiteral:
+		return object.BOOLEAN_OBJ, expression.Token, true
+	case *ast.StringLiteral:
+		return object.STRING_OBJ, expression.Token, true
+	case *ast.ArrayLiteral:
+		return object.ARRAY_OBJ, expression.Token, true
+	case *ast.MapLiteral:
+		return object.MAP_OBJ, expression.Token, true
+	case *ast.NullLiteral:
+		return object.NULL_OBJ, expression.Token, true
+	}
+
+	return "", token.Token{}, false
+}
+
+// inference infers type arguments from static types like object.TypeArguments does from values
+type inference struct {
+	declarations *declarations
+	parameters   map[string]*ast.TypeParameter
+	inferred     map[string]string
+}
+
+func (d *declarations) inference(typeParameters []*ast.TypeParameter) *inference {
+	inf := &inference{declarations: d, parameters: make(map[string]*ast.TypeParameter), inferred: make(map[string]string)}
+	for _, tp := range typeParameters {
+		inf.parameters[tp.Name.Value] = tp
+	}
+
+	return inf
+}
+
+// bind infers the type argument of the type parameter named by typeName, it describes the conflict with the type
+// argument inferred before or the constraint. The description is empty when there is none.
+func (inf *inference) bind(typeName, actual string) string {
+	tp, ok := inf.parameters[typeName]
+	if !ok {
+		return ""
+	}
+
+	if inferred, ok := inf.inferred[typeName]; ok && inferred != actual {
+		return fmt.Sprintf("type parameter %s inferred as %s and %s", typeName, inferred, actual)
+	}
+
+	if tp.Constraint != nil && inf.declarations.unique(tp.Constraint.Value) {
+		if signatures, ok := inf.declarations.interfaces[tp.Constraint.Value]; ok {
+			if mismatch := inf.declarations.implements(actual, tp.Constraint.Value, signatures); mismatch != "" {
+				return fmt.Sprintf("type parameter %s: %s", typeName, mismatch)
+			}
+		}
+	}
+
+	inf.inferred[typeName] = actual
+
+	return ""
+}
@@ -0,0 +1,56 @@
+package vet
+
+import (
+	"fmt"
+
+	"Flow/src/ast"
+	"Flow/src/token"
+)
+
+// duplicateCases reports constant case values occurring more than once in a switch, only the first can ever match
+func duplicateCases(node ast.Node, report func(tok token.Token, format string, args ...interface{})) {
+	switchExpression, ok := node.(*ast.SwitchExpression)
+	if !ok {
+		return
+	}
+
+	seen := make(map[string]token.Token)
+
+	for _, switchCase := range switchExpression.Cases {
+		for _, value := range switchCase.Values {
+			key, tok, ok := constant(value)
+			if !ok {
+				continue
+			}
+
+			if previous, ok := seen[key]; ok {
+				report(tok, "duplicate case %s in switch, previous case at %d:%d", value.String(), previous.Line, previous.Pos)
+				continue
+			}
+
+			seen[key] = tok
+		}
+	}
+}
+
+// constant returns a key identifying the value of a constant expression and the token it starts at, the type is part
+// of the key so 1 and "1" don't collide
+func constant(expression ast.Expression) (string, token.Token, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return fmt.Sprintf("int:%d", expression.Value), expression.Token, true
+	case *ast.BooleanLiteral:
+		return fmt.Sprintf("bool:%t", expression.Value), expression.Token, true
+	case *ast.PrefixExpression:
+		if integer, ok := expression.Right.(*ast.IntegerLiteral); ok && expression.Operator == "-" {
+			return fmt.Sprintf("int:%d", -integer.Value), expression.Token, true
+		}
+	case *ast.StringLiteral:
+		part := expression.StringParts.Value
+		if !expression.StringParts.HasNext() && part.Expr == nil && part.CharacterString != nil {
+			return "string:" + *part.CharacterString, expression.Token, true
+		}
+	}
+
+	return "", token.Token{}, false
+}
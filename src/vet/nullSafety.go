@@ -0,0 +1,162 @@
+package vet
+
+import (
+	"Flow/src/ast"
+	"Flow/src/token"
+)
+
+// nullSafety reports uses of a nullable or optional parameter which fail when it is null: accessing a field or method
+// without ?., indexing, calling it and arithmetic. The parameter is narrowed to non null inside a branch only taken when
+// it isn't null, e.g. if x != null { x.y }, and in the statements following an if returning when it is null.
+func nullSafety(node ast.Node, report func(tok token.Token, format string, args ...interface{})) {
+	function, ok := node.(*ast.FunctionLiteralExpression)
+	if !ok {
+		return
+	}
+
+	nullable := make(map[string]bool)
+	for _, parameter := range function.Parameters {
+		if (parameter.Type != nil && parameter.Nullable || parameter.Optional) && !parameter.Name.IsBlank() {
+			nullable[parameter.Name.Value] = true
+		}
+	}
+
+	if len(nullable) == 0 || function.Body == nil {
+		return
+	}
+
+	n := &narrowing{nullable: nullable, report: report}
+	n.block(function.Body, map[string]bool{})
+}
+
+type narrowing struct {
+	nullable map[string]bool
+	report   func(tok token.Token, format string, args ...interface{})
+}
+
+// block checks the statements in order, the narrowing of a statement applies to the statements following it
+func (n *narrowing) block(block *ast.BlockStatement, narrowed map[string]bool) {
+	narrowed = with(narrowed, nil)
+
+	for _, statement := range block.Statements {
+		n.check(statement, narrowed)
+
+		for _, name := range returnsWhenNull(statement) {
+			narrowed[name] = true
+		}
+
+		// a let shadows the parameter
+		if let, ok := statement.(*ast.LetStatement); ok {
+			for _, name := range let.Names() {
+				if n.nullable[name.Value] {
+					narrowed[name.Value] = true
+				}
+			}
+		}
+	}
+}
+
+func (n *narrowing) check(node ast.Node, narrowed map[string]bool) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FunctionLiteralExpression:
+			// a nested function can be called when the narrowing doesn't hold anymore, only its own parameters are
+			// checked
+			return false
+		case *ast.BlockStatement:
+			n.block(node, narrowed)
+			return false
+		case *ast.IfExpression:
+			n.check(node.Condition, narrowed)
+			n.block(node.Consequence, with(narrowed, comparedToNull(node.Condition, "!=")))
+			if node.Alternative != nil {
+				n.block(node.Alternative, with(narrowed, comparedToNull(node.Condition, "==")))
+			}
+			return false
+		case *ast.TernaryExpression:
+			n.check(node.Condition, narrowed)
+			n.check(node.Consequence, with(narrowed, comparedToNull(node.Condition, "!=")))
+			n.check(node.Alternative, with(narrowed, comparedToNull(node.Condition, "==")))
+			return false
+		case *ast.FieldAccessExpression:
+			if !node.IsSafe() {
+				n.use(node.Left, narrowed)
+			}
+		case *ast.IndexExpression:
+			n.use(node.Left, narrowed)
+		case *ast.CallExpression:
+			n.use(node.Function, narrowed)
+		case *ast.InfixExpression:
+			switch node.Operator {
+			case "+", "-", "*", "/", "<", ">":
+				n.use(node.Left, narrowed)
+				n.use(node.Right, narrowed)
+			}
+		}
+		return true
+	})
+}
+
+func (n *narrowing) use(expression ast.Expression, narrowed map[string]bool) {
+	identifier, ok := expression.(*ast.IdentifierLiteral)
+	if !ok || !n.nullable[identifier.Value] || narrowed[identifier.Value] {
+		return
+	}
+
+	n.report(identifier.Token, "%s is nullable, check it against null before using it", identifier.Value)
+}
+
+// comparedToNull returns the name compared to null by the condition with the operator, e.g. x for x != null
+func comparedToNull(condition ast.Expression, operator string) []string {
+	infix, ok := condition.(*ast.InfixExpression)
+	if !ok || infix.Operator != operator {
+		return nil
+	}
+
+	left, leftIsIdentifier := infix.Left.(*ast.IdentifierLiteral)
+	right, rightIsIdentifier := infix.Right.(*ast.IdentifierLiteral)
+	_, leftIsNull := infix.Left.(*ast.NullLiteral)
+	_, rightIsNull := infix.Right.(*ast.NullLiteral)
+
+	switch {
+	case leftIsIdentifier && rightIsNull:
+		return []string{left.Value}
+	case rightIsIdentifier && leftIsNull:
+		return []string{right.Value}
+	default:
+		return nil
+	}
+}
+
+// returnsWhenNull returns the name checked by an if without else returning when it is null, e.g. if x == null { return 0 }
+func returnsWhenNull(statement ast.Statement) []string {
+	expressionStatement, ok := statement.(*ast.ExpressionStatement)
+	if !ok {
+		return nil
+	}
+
+	ifExpression, ok := expressionStatement.Expression.(*ast.IfExpression)
+	if !ok || ifExpression.Alternative != nil || len(ifExpression.Consequence.Statements) == 0 {
+		return nil
+	}
+
+	statements := ifExpression.Consequence.Statements
+	if _, ok := statements[len(statements)-1].(*ast.ReturnStatement); !ok {
+		return nil
+	}
+
+	return comparedToNull(ifExpression.Condition, "==")
+}
+
+// with copies the narrowed names adding the given names
+func with(narrowed map[string]bool, names []string) map[string]bool {
+	result := make(map[string]bool, len(narrowed)+len(names))
+	for name := range narrowed {
+		result[name] = true
+	}
+	for _, name := range names {
+		result[name] = true
+	}
+
+	return result
+}
@@ -0,0 +1,227 @@
+package vet
+
+import (
+	"fmt"
+	"strings"
+
+	"Flow/src/ast"
+	"Flow/src/object"
+	"Flow/src/token"
+	"Flow/src/utility/slice"
+)
+
+// overloadResolution resolves calls of functions overloaded by top level lets like the evaluator does, it reports the
+// calls matching no overload or several equally specific ones. Only calls whose argument types are known without
+// evaluating them are resolved, and only names declared by nothing but the overloads. A call is resolved against the
+// overloads declared before the statement holding it. A call in a function body runs when the function is called,
+// which may be after more overloads are declared, so it is only reported when the overloads declared by the whole
+// program resolve it the same way.
+func overloadResolution(node ast.Node, report func(tok token.Token, format string, args ...interface{})) {
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return
+	}
+
+	d := declare(program)
+	before, all := declareOverloads(program, d)
+
+	for i, statement := range program.Statements {
+		var inspect func(node ast.Node, inFunction bool)
+		inspect = func(node ast.Node, inFunction bool) {
+			ast.Inspect(node, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.FunctionLiteralExpression:
+					if !inFunction {
+						inspect(node.Body, true)
+						return false
+					}
+				case *ast.CallExpression:
+					if function, ok := node.Function.(*ast.IdentifierLiteral); ok {
+						d.checkOverloadedCall(function.Value, node, before[i], all, inFunction, report)
+					}
+				}
+				return true
+			})
+		}
+		inspect(statement, false)
+	}
+}
+
+// checkOverloadedCall reports the call when it resolves to no or several overloads of those declared before it, a call
+// in a function body has to resolve the same way against all overloads
+func (d *declarations) checkOverloadedCall(name string, call *ast.CallExpression, before, all map[string][]*ast.FunctionLiteralExpression, inFunction bool, report func(tok token.Token, format string, args ...interface{})) {
+	functions, ok := before[name]
+	if !ok {
+		return
+	}
+
+	message := d.resolve(name, functions, call)
+	if message == "" || inFunction && d.resolve(name, all[name], call) != message {
+		return
+	}
+
+	report(call.Token, "%s", message)
+}
+
+// declareOverloads collects the functions declared under the same name by top level lets, a function with the same
+// signature as a preceding one replaces it. The overloads declared before each statement are returned along with the
+// overloads declared by all of them.
+func declareOverloads(program *ast.Program, d *declarations) (before []map[string][]*ast.FunctionLiteralExpression, all map[string][]*ast.FunctionLiteralExpression) {
+	overloads := make(map[string][]*ast.FunctionLiteralExpression)
+	declared, functions := make(map[string]int), make(map[string]int)
+
+	for _, statement := range program.Statements {
+		before = append(before, copyOverloads(overloads))
+
+		let, ok := statement.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+
+		for _, name := range let.Names() {
+			declared[name.Value]++
+		}
+
+		function, ok := let.Value.(*ast.FunctionLiteralExpression)
+		if !ok || let.Pattern != nil {
+			continue
+		}
+		functions[let.Name.Value]++
+
+		replaced := false
+		for i, existing := range overloads[let.Name.Value] {
+			if object.SameParameters(existing.Parameters, function.Parameters) {
+				overloads[let.Name.Value][i] = function
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			overloads[let.Name.Value] = append(overloads[let.Name.Value], function)
+		}
+	}
+
+	// a name declared by anything else, like a parameter or another value, may not refer to the overloads. A call
+	// preceding the second declaration of an overloaded name resolves to the single function declared before it.
+	for _, declaredOverloads := range append(before, overloads) {
+		for name := range declaredOverloads {
+			if len(overloads[name]) < 2 || declared[name] != functions[name] || declared[name] != d.counts[name] {
+				delete(declaredOverloads, name)
+			}
+		}
+	}
+
+	return before, overloads
+}
+
+func copyOverloads(overloads map[string][]*ast.FunctionLiteralExpression) map[string][]*ast.FunctionLiteralExpression {
+	copied := make(map[string][]*ast.FunctionLiteralExpression, len(overloads))
+	for name, functions := range overloads {
+		copied[name] = append([]*ast.FunctionLiteralExpression(nil), functions...)
+	}
+	return copied
+}
+
+// resolve describes why the call doesn't resolve to a single one of the functions, it is empty when it does or the
+// argument types aren't known
+func (d *declarations) resolve(name string, functions []*ast.FunctionLiteralExpression, call *ast.CallExpression) string {
+	var argumentTypes []string
+	for _, argument := range call.Arguments {
+		typeName, _, ok := d.staticType(argument)
+		if !ok {
+			return ""
+		}
+		argumentTypes = append(argumentTypes, typeName)
+	}
+
+	var (
+		matching      []*ast.FunctionLiteralExpression
+		specificities [][]int
+	)
+
+	for _, function := range functions {
+		if specificity, ok := d.matchArguments(function, argumentTypes); ok {
+			matching = append(matching, function)
+			specificities = append(specificities, specificity)
+		}
+	}
+
+	if len(matching) == 0 {
+		return fmt.Sprintf("no overload of %s matches %s(%s), candidates: %s", name, name, strings.Join(argumentTypes, ", "), signatures(name, functions))
+	}
+
+	if best := object.MostSpecific(specificities); len(best) > 1 {
+		ambiguous := slice.Map(best, func(i int) *ast.FunctionLiteralExpression { return matching[i] })
+		return fmt.Sprintf("ambiguous call %s(%s), candidates: %s", name, strings.Join(argumentTypes, ", "), signatures(name, ambiguous))
+	}
+
+	return ""
+}
+
+// matchArguments returns how specifically each parameter matches the static type of its argument like the evaluator
+// does for the values, it reports false when the function doesn't accept them
+func (d *declarations) matchArguments(function *ast.FunctionLiteralExpression, argumentTypes []string) ([]int, bool) {
+	if !ast.TakesArguments(function.Parameters, len(argumentTypes)) {
+		return nil, false
+	}
+
+	inferred := d.inference(function.TypeParameters)
+
+	var specificity []int
+	for i, typeName := range argumentTypes {
+		parameter, _ := ast.ParameterAt(function.Parameters, i)
+		s, ok := d.matchParameter(parameter, typeName, inferred)
+		if !ok {
+			return nil, false
+		}
+		specificity = append(specificity, s)
+	}
+
+	return append(specificity, object.MatchesArity(function.Parameters, len(argumentTypes))), true
+}
+
+func (d *declarations) matchParameter(parameter *ast.Parameter, typeName string, inferred *inference) (int, bool) {
+	if parameter.Type == nil {
+		return object.MatchesAny, true
+	}
+
+	if typeName == object.NULL_OBJ {
+		return object.MatchesExactly, parameter.AcceptsNull()
+	}
+
+	if tp, ok := inferred.parameters[parameter.Type.Value]; ok {
+		if mismatch := inferred.bind(parameter.Type.Value, typeName); mismatch != "" {
+			return 0, false
+		}
+
+		if tp.Constraint != nil {
+			return object.MatchesInterface, true
+		}
+		return object.MatchesTypeParameter, true
+	}
+
+	if builtin, ok := object.BuiltinType(parameter.Type.Value); ok {
+		return object.MatchesExactly, string(builtin) == typeName
+	}
+
+	if !d.unique(parameter.Type.Value) {
+		return object.MatchesAny, true
+	}
+
+	if signatures, ok := d.interfaces[parameter.Type.Value]; ok {
+		return object.MatchesInterface, d.implements(typeName, parameter.Type.Value, signatures) == ""
+	}
+
+	if _, ok := d.structs[parameter.Type.Value]; ok {
+		return object.MatchesExactly, typeName == parameter.Type.Value
+	}
+
+	return object.MatchesAny, true
+}
+
+// signatures formats the functions as overloads of the name separated by commas
+func signatures(name string, functions []*ast.FunctionLiteralExpression) string {
+	return strings.Join(slice.Map(functions, func(function *ast.FunctionLiteralExpression) string {
+		return fmt.Sprint(&ast.Signature{Name: &ast.IdentifierLiteral{Value: name}, Parameters: function.Parameters})
+	}), ", ")
+}
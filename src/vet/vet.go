@@ -0,0 +1,53 @@
+package vet
+
+import (
+	"fmt"
+	"sort"
+
+	"Flow/src/ast"
+	"Flow/src/token"
+)
+
+// Diagnostic is a suspicious construct found in a program that parses fine
+type Diagnostic struct {
+	Token   token.Token
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", d.Token.Line, d.Token.Pos, d.Message)
+}
+
+// check inspects a single node and reports its diagnostics
+type check func(node ast.Node, report func(tok token.Token, format string, args ...interface{}))
+
+var checks = []check{
+	duplicateCases,
+	argumentTypes,
+	nullSafety,
+	overloadResolution,
+}
+
+// Vet runs all checks over the program, diagnostics are ordered by their position in the program and those at the same
+// position by the order the checks report them
+func Vet(program *ast.Program) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	report := func(tok token.Token, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{Token: tok, Message: fmt.Sprintf(format, args...)})
+	}
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		for _, c := range checks {
+			c(node, report)
+		}
+		return true
+	})
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Token, diagnostics[j].Token
+		return a.Line < b.Line || a.Line == b.Line && a.Pos < b.Pos
+	})
+
+	return diagnostics
+}
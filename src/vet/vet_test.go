@@ -0,0 +1,372 @@
+package vet
+
+import (
+	"strings"
+	"testing"
+
+	"Flow/src/parser"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (test *Suite) TestDuplicateCases() {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"switch x { case 1, 2: 1 case 3: 2 }", nil},
+		{"switch x { case 1, 2: 1 case 2: 2 }", []string{"1:30: duplicate case 2 in switch, previous case at 1:20"}},
+		{"switch x {\ncase -1: 1\ncase 1, -1: 2\n}", []string{"3:9: duplicate case (-1) in switch, previous case at 2:6"}},
+		{`switch x { case "a", 1: 1 case "1", "a", true: 2 case true: 3 }`, []string{
+			`1:37: duplicate case "a" in switch, previous case at 1:17`,
+			`1:55: duplicate case true in switch, previous case at 1:42`,
+		}},
+		{`switch x { case "a${y}", y: 1 case "a${y}", y: 2 }`, nil},
+		{"let f = () => switch { case x > 1: 1 case x > 1: 2 };", nil},
+		{"let f = () => { switch y { case 0: switch z { case 4: 1 case 4: 2 } } };", []string{
+			"1:62: duplicate case 4 in switch, previous case at 1:52",
+		}},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), tt.input, 1)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestInterfaceConformance() {
+	const declarations = "type Shape interface { area(); scale(factor int) }\n" +
+		"type Square struct { side; area() => 1; scale(f int) => 2 }\n" +
+		"type Circle struct { r; area() => 1 }\n" +
+		"type Line struct { area() => 1; scale(a, b) => 2 }\n" +
+		"let describe = (s Shape, label) => s.area()\n"
+
+	tests := []struct {
+		input    string
+		stmts    int
+		expected []string
+	}{
+		{"describe(Square{side: 1}, 1)", 1, nil},
+		{"describe(Circle{}, 1)", 1, []string{"6:10: argument s: Circle does not implement Shape: missing method scale(factor int)"}},
+		{"let c = Circle{}\ndescribe(c, Circle{})", 2, []string{"7:10: argument s: Circle does not implement Shape: missing method scale(factor int)"}},
+		{"describe(Line{}, 1)", 1, []string{"6:10: argument s: Line does not implement Shape: method scale has signature scale(a, b), expected scale(factor int)"}},
+		{"describe(5, 1)", 1, []string{"6:10: argument s: INTEGER does not implement Shape: missing method area()"}},
+		{"let c = Circle{}\nc = Square{}\ndescribe(c, 1)", 3, nil},
+		{"let f = (c) => describe(c, 1)", 1, nil},
+		{"let g = () => { let describe = (x) => x; describe(1, 1) }", 1, nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 5+tt.stmts)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestInterfaceReturnTypes() {
+	const declarations = "type Sized interface { size() int }\n" +
+		"type Box struct { size() int => 1 }\n" +
+		"type Text struct { size() string => \"1\" }\n" +
+		"let measure = (s Sized) => s.size()\n"
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"measure(Box{})", nil},
+		{"measure(Text{})", []string{"5:9: argument s: Text does not implement Sized: method size has signature size() string, expected size() int"}},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 5)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestGenericTypeArguments() {
+	const declarations = "type Shape interface { area() }\n" +
+		"type Square struct { side; area() => 1 }\n" +
+		"type Pair<T> struct { left T; right T; label }\n" +
+		"let first = <T>(a T, b T, c) => a\n" +
+		"let areaOf = <S Shape>(s S) => s.area()\n"
+
+	tests := []struct {
+		input    string
+		stmts    int
+		expected []string
+	}{
+		{`first(1, 2, "c")`, 1, nil},
+		{`first(1, "b", 3)`, 1, []string{"6:10: argument b: type parameter T inferred as INTEGER and STRING"}},
+		{"let sq = Square{}\nfirst(sq, Square{}, 1)", 2, nil},
+		{"areaOf(Square{})", 1, nil},
+		{"areaOf([1])", 1, []string{"6:8: argument s: type parameter S: ARRAY does not implement Shape: missing method area()"}},
+		{`Pair{left: 1, right: 2, label: "x"}`, 1, nil},
+		{`Pair{left: 1, right: true}`, 1, []string{"6:15: field right: type parameter T inferred as INTEGER and BOOLEAN"}},
+		{"let f = (x) => first(x, 1, 2)", 1, nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 5+tt.stmts)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestNullSafety() {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let f = (p Point?) => p.x", []string{"1:23: p is nullable, check it against null before using it"}},
+		{"let f = (p Point?) => p?.x", nil},
+		{"let f = (p Point?) => p?.x ?? 0", nil},
+		{"let f = (p Point) => p.x", nil},
+		{"let f = (p Point?) => if p != null { p.x }", nil},
+		{"let f = (p Point?) => if null != p { p.x } else { p.y }", []string{"1:51: p is nullable, check it against null before using it"}},
+		{"let f = (p Point?) => if p == null { 0 } else { p.x }", nil},
+		{"let f = (p Point?) => p != null ? p.x : 0", nil},
+		{"let f = (p Point?) => { if p == null { return 0 }; p.x }", nil},
+		{"let f = (p Point?) => { if p == null { 0 }; p.x }", []string{"1:45: p is nullable, check it against null before using it"}},
+		{"let f = (xs Array?, g Fn?) => [xs[0], g(), xs + 1]", []string{
+			"1:32: xs is nullable, check it against null before using it",
+			"1:39: g is nullable, check it against null before using it",
+			"1:44: xs is nullable, check it against null before using it",
+		}},
+		{"let f = (p Point?) => if p != null { () => p.x }", nil},
+		{"let f = (p Point?) => { let p = 1; p + 1 }", nil},
+		{"let f = (p Point?) => { let q, p = 1, 2; p + q }", nil},
+		{"let f = (p?) => p.x", []string{"1:17: p is nullable, check it against null before using it"}},
+		{"let f = (p = Point{x: 1}) => p.x", nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), tt.input, 1)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestNullArguments() {
+	const declarations = "type Point struct { x int; y int? }\n" +
+		"let f = (a int, b int?) => a\n" +
+		"let g = (a int, b? int, ...c int) => a\n"
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"f(1, null)", nil},
+		{"f(null, 1)", []string{"4:3: argument a: int is not nullable"}},
+		{"Point{x: 1}", nil},
+		{"Point{x: null, y: null}", []string{"4:7: field x: int is not nullable"}},
+		{"Point{y: 1}", []string{"4:1: field x: int is not nullable"}},
+		{"g(1, null, 2, 3)", nil},
+		{"g(1, 2, 3, null)", []string{"4:12: argument c: int is not nullable"}},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 4)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestBuiltinAndStructTypes() {
+	const declarations = "type Point struct { x int; label string? }\n" +
+		"type Line struct { a; b }\n" +
+		"let f = (x int, s string?, p Point?) => x\n"
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`f(1, "s", Point{x: 1})`, nil},
+		{`f("s")`, []string{"4:3: argument x: STRING is not of type int"}},
+		{`f(1, 2, Line{})`, []string{"4:6: argument s: INTEGER is not of type string", "4:9: argument p: Line is not of type Point"}},
+		{`Point{x: "1", label: true}`, []string{"4:7: field x: STRING is not of type int", "4:15: field label: BOOLEAN is not of type string"}},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 4)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestBuiltinSignatures() {
+	const declarations = "type Point struct { x int }\n" +
+		"let positive = (x int) bool => x > 0\n"
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`filter(positive, [1, 2, null])`, []string{"3:25: argument values: int is not nullable"}},
+		{`filter((x, i) => i > 0, [1, "a", true])`, nil},
+		{`filter(positive, [1, "a"])`, []string{"3:22: argument values: type parameter T inferred as INTEGER and STRING"}},
+		{`filter(positive)`, nil},
+		{`filter(1, [1])`, []string{"3:8: argument predicate: INTEGER is not a function"}},
+		{`filter(positive, 5)`, []string{"3:18: argument values: INTEGER is not iterable"}},
+		{`filter((a, b, c) => a, [1])`, []string{"3:8: argument predicate: function takes at least 3 arguments, filter passes 2"}},
+		{`filter((x) string => "a", [1])`, []string{"3:8: argument predicate: function returns STRING, filter needs BOOLEAN"}},
+		{`filter((x) => 1, [1, 2])`, []string{"3:8: argument predicate: function returns INTEGER, filter needs BOOLEAN"}},
+		{`filter(x => { return "a" }, [1])`, []string{"3:8: argument predicate: function returns STRING, filter needs BOOLEAN"}},
+		{`filter((x) => true, [1])`, nil},
+		{`filter((x) => x, [true])`, nil},
+		{`filter((x) => { let y = 1; y }, [1])`, nil},
+		{`reduce((acc int, x int) => acc + x, 0, [1, 2])`, nil},
+		{`reduce((acc int, x) => acc, "", [1])`, []string{"3:29: argument initial: type parameter A inferred as INTEGER and STRING"}},
+		{`reduce((acc int, x) string => "a", 0, [1])`, []string{"3:8: argument reducer: type parameter A inferred as INTEGER and STRING"}},
+		{`reduce((acc, x Point) => acc, 0, [Point{x: 1}, 2])`, []string{"3:48: argument values: type parameter T inferred as Point and INTEGER"}},
+		{`reduce((acc, x) string => acc, 0, [1])`, []string{"3:32: argument initial: type parameter A inferred as STRING and INTEGER"}},
+		{"let filter = (a, b) => a\nfilter(1, 2)", nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 2+strings.Count(tt.input, "\n")+1)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}
+
+func (test *Suite) TestOverloadResolution() {
+	const declarations = "type Shape interface { area() }\n" +
+		"type Square struct { side; area() => 1 }\n" +
+		"let show = (x int) => x\n" +
+		"let show = (x string) => x\n" +
+		"let show = (s Shape, label) => label\n" +
+		"let show = (s Square, label string?) => label\n"
+
+	tests := []struct {
+		input    string
+		stmts    int
+		expected []string
+	}{
+		{`show(1)`, 1, nil},
+		{`show("a")`, 1, nil},
+		{`show(Square{}, "a")`, 1, nil},
+		{`show(true)`, 1, []string{"7:5: no overload of show matches show(BOOLEAN), candidates: show(x int), show(x string), show(s Shape, label), show(s Square, label string?)"}},
+		{`show(1, 2, 3)`, 1, []string{"7:5: no overload of show matches show(INTEGER, INTEGER, INTEGER), candidates: show(x int), show(x string), show(s Shape, label), show(s Square, label string?)"}},
+		{`show(Square{}, 1)`, 1, nil},
+		{"let f = (x) => show(x)", 1, nil},
+		{"let f = (show) => show(true)", 1, nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), declarations+tt.input, 6+tt.stmts)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+
+	program := parser.CreateProgram(test.T(), "let f = (x int, y) => 1\nlet f = (x, y int) => 2\nf(1, 2)", 3)
+	diagnostics := Vet(program)
+	if test.Len(diagnostics, 1) {
+		test.Equal("3:2: ambiguous call f(INTEGER, INTEGER), candidates: f(x int, y), f(x, y int)", diagnostics[0].String())
+	}
+}
+
+func (test *Suite) TestDiagnosticOrder() {
+	program := parser.CreateProgram(test.T(), "let g = (p Point?) => p.x\nlet h = (a int) => a\nlet h = (a bool) => a\nh(\"s\")", 4)
+
+	var diagnostics []string
+	for _, diagnostic := range Vet(program) {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	test.Equal([]string{
+		"1:23: p is nullable, check it against null before using it",
+		"4:2: no overload of h matches h(STRING), candidates: h(a int), h(a bool)",
+	}, diagnostics)
+}
+
+func (test *Suite) TestOverloadDeclarationOrder() {
+	tests := []struct {
+		input    string
+		stmts    int
+		expected []string
+	}{
+		{"let f = (x int, y) => 1\nprint(f(1, 2))\nlet f = (x, y int) => 2", 3, nil},
+		{"let h = (a int) => a\nlet h = (a bool) => a\nh(\"s\")\nlet h = (a string) => a", 4, []string{
+			"3:2: no overload of h matches h(STRING), candidates: h(a int), h(a bool)",
+		}},
+		{"let h = (a int) => a\nlet h = (a bool) => a\nh(\"s\")", 3, []string{
+			"3:2: no overload of h matches h(STRING), candidates: h(a int), h(a bool)",
+		}},
+		{"let h = (a int) => a\nlet h = (a bool) => a\nlet g = () => h(\"s\")\nlet h = (a string) => a\ng()", 5, nil},
+		{"let h = (a int) => a\nlet h = (a bool) => a\nlet g = () => h(\"s\")\nlet h = (a string?) => a\ng()", 5, nil},
+		{"let h = (a int) => a\nlet h = (a bool) => a\nlet g = () => h(\"s\")", 3, []string{
+			"3:16: no overload of h matches h(STRING), candidates: h(a int), h(a bool)",
+		}},
+		{"let g = () => h(\"s\")\nlet h = (a int) => a\nlet h = (a bool) => a", 3, nil},
+		{"let h = (a int) => a\nh(\"s\")\nlet h = (a string) => a", 3, []string{
+			"2:2: no overload of h matches h(STRING), candidates: h(a int)",
+		}},
+		{"let h = (a int) => a\nh(1)\nlet h = (a string) => a\nh(\"s\")", 4, nil},
+	}
+
+	for _, tt := range tests {
+		program := parser.CreateProgram(test.T(), tt.input, tt.stmts)
+
+		var diagnostics []string
+		for _, diagnostic := range Vet(program) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+
+		test.Equal(tt.expected, diagnostics, tt.input)
+	}
+}